@@ -0,0 +1,316 @@
+//go:build ignore
+
+// This program generates pathlib_unicode.go from the Unicode Character Database.
+// It is invoked by go generate and downloads the required files of the passed
+// Unicode version, unless a directory containing them is passed using -ucd.
+//
+//	go run gen_unicode.go [-version 14.0.0] [-ucd dir] [-output pathlib_unicode.go]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	version = flag.String("version", "14.0.0", "Unicode version to generate the tables for")
+	ucdDir  = flag.String("ucd", "", "directory containing the UCD files, downloaded if empty")
+	output  = flag.String("output", "pathlib_unicode.go", "file to write the tables to")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen_unicode: ")
+	flag.Parse()
+
+	decompositions := make(map[rune][]rune)
+	combiningClasses := make(map[rune]uint8)
+	err := parseUCD("UnicodeData.txt", func(fields []string) error {
+		cp, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+
+		class, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return err
+		}
+		if class != 0 {
+			combiningClasses[cp] = uint8(class)
+		}
+
+		// compatibility decompositions start with a formatting tag like <font>
+		if fields[5] == "" || strings.HasPrefix(fields[5], "<") {
+			return nil
+		}
+
+		decomposition, err := parseRunes(fields[5])
+		if err != nil {
+			return err
+		}
+		if len(decomposition) > 2 {
+			return fmt.Errorf("decomposition of %04X has %d characters", cp, len(decomposition))
+		}
+
+		decompositions[cp] = decomposition
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	excluded := make(map[rune]bool)
+	err = parseUCD("DerivedNormalizationProps.txt", func(fields []string) error {
+		if len(fields) != 2 || fields[1] != "Full_Composition_Exclusion" {
+			return nil
+		}
+
+		lo, hi, err := parseRange(fields[0])
+		if err != nil {
+			return err
+		}
+
+		for cp := lo; cp <= hi; cp++ {
+			excluded[cp] = true
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	caseFoldings := make(map[rune][]rune)
+	err = parseUCD("CaseFolding.txt", func(fields []string) error {
+		// simple and Turkic foldings are not part of the full case folding
+		if fields[1] != "C" && fields[1] != "F" {
+			return nil
+		}
+
+		cp, err := parseRune(fields[0])
+		if err != nil {
+			return err
+		}
+
+		folding, err := parseRunes(fields[2])
+		if err != nil {
+			return err
+		}
+
+		caseFoldings[cp] = folding
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"go run gen_unicode.go\"; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package pathlib\n\n")
+	fmt.Fprintf(&b, "/*\nThe following tables are derived from the Unicode Character Database %s\n", *version)
+	fmt.Fprintf(&b, "and are used by normalizeNFD, normalizeNFC and foldCase. Hangul syllables\n")
+	fmt.Fprintf(&b, "are decomposed and composed algorithmically and are therefore not included.\n*/\n\n")
+
+	fmt.Fprintf(&b, "/*\ncanonicalDecompositions maps characters to their canonical decomposition\n")
+	fmt.Fprintf(&b, "of one or two characters, which may have to be decomposed further.\n")
+	fmt.Fprintf(&b, "Single character decompositions have a second character of 0.\n*/\n")
+	fmt.Fprintf(&b, "var canonicalDecompositions = map[rune][2]rune{\n")
+	for _, cp := range sortedKeys(decompositions) {
+		decomposition := append(decompositions[cp], 0)
+		fmt.Fprintf(&b, "\t0x%04X: {0x%04X, 0x%04X},\n", cp, decomposition[0], decomposition[1])
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	// primary composites are all pairs that are not excluded from composition
+	compositions := make(map[[2]rune]rune)
+	for cp, decomposition := range decompositions {
+		if len(decomposition) == 2 && !excluded[cp] {
+			compositions[[2]rune(decomposition)] = cp
+		}
+	}
+
+	pairs := make([][2]rune, 0, len(compositions))
+	for pair := range compositions {
+		pairs = append(pairs, pair)
+	}
+	slices.SortFunc(pairs, func(a, b [2]rune) int {
+		return slices.Compare(a[:], b[:])
+	})
+
+	fmt.Fprintf(&b, "/*\ncanonicalCompositions maps pairs of characters to their primary composite.\n*/\n")
+	fmt.Fprintf(&b, "var canonicalCompositions = map[[2]rune]rune{\n")
+	for _, pair := range pairs {
+		fmt.Fprintf(&b, "\t{0x%04X, 0x%04X}: 0x%04X,\n", pair[0], pair[1], compositions[pair])
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "/*\ncombiningClassRange is a range of characters with the same non-zero canonical combining class.\n*/\n")
+	fmt.Fprintf(&b, "type combiningClassRange struct {\n\tlo    rune\n\thi    rune\n\tclass uint8\n}\n\n")
+	fmt.Fprintf(&b, "/*\ncombiningClasses contains the sorted ranges of all characters with a non-zero canonical combining class.\n*/\n")
+	fmt.Fprintf(&b, "var combiningClasses = []combiningClassRange{\n")
+	codepoints := sortedKeys(combiningClasses)
+	for i := 0; i < len(codepoints); {
+		// consecutive characters of the same class are merged into one range
+		lo, class := codepoints[i], combiningClasses[codepoints[i]]
+		hi := lo
+		for i++; i < len(codepoints) && codepoints[i] == hi+1 && combiningClasses[codepoints[i]] == class; i++ {
+			hi++
+		}
+		fmt.Fprintf(&b, "\t{0x%04X, 0x%04X, %d},\n", lo, hi, class)
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "/*\ncaseFoldings maps characters to their full case folding, excluding the\n")
+	fmt.Fprintf(&b, "locale-specific mappings for Turkic languages.\n*/\n")
+	fmt.Fprintf(&b, "var caseFoldings = map[rune]string{\n")
+	for _, cp := range sortedKeys(caseFoldings) {
+		fmt.Fprintf(&b, "\t0x%04X: %s,\n", cp, quote(caseFoldings[cp]))
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*output, source, 0666)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+/*
+parseUCD calls the passed function with the semicolon-separated and trimmed fields
+of every line of a UCD file, ignoring comments and empty lines.
+*/
+func parseUCD(name string, fn func(fields []string) error) error {
+	reader, err := openUCD(name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		err = fn(fields)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+/*
+openUCD opens the passed UCD file from the -ucd directory or downloads it.
+*/
+func openUCD(name string) (io.ReadCloser, error) {
+	if *ucdDir != "" {
+		return os.Open(filepath.Join(*ucdDir, name))
+	}
+
+	url := fmt.Sprintf("https://www.unicode.org/Public/%s/ucd/%s", *version, name)
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("downloading %s: %s", url, response.Status)
+	}
+
+	return response.Body, nil
+}
+
+/*
+parseRune parses a hexadecimal code point like "00C0".
+*/
+func parseRune(s string) (rune, error) {
+	cp, err := strconv.ParseUint(s, 16, 32)
+	return rune(cp), err
+}
+
+/*
+parseRunes parses space-separated hexadecimal code points like "0041 0300".
+*/
+func parseRunes(s string) ([]rune, error) {
+	var runes []rune
+	for _, field := range strings.Fields(s) {
+		cp, err := parseRune(field)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, cp)
+	}
+
+	return runes, nil
+}
+
+/*
+parseRange parses a code point or a range of code points like "0340..0341".
+*/
+func parseRange(s string) (rune, rune, error) {
+	first, last, found := strings.Cut(s, "..")
+	lo, err := parseRune(first)
+	if err != nil || !found {
+		return lo, lo, err
+	}
+
+	hi, err := parseRune(last)
+	return lo, hi, err
+}
+
+/*
+quote returns a Go string literal of the passed runes, escaping everything but printable ASCII.
+*/
+func quote(runes []rune) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range runes {
+		switch {
+		case r >= 0x20 && r < 0x7f && r != '"' && r != '\\':
+			b.WriteRune(r)
+		case r < 0x10000:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			fmt.Fprintf(&b, `\U%08x`, r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+/*
+sortedKeys returns the sorted keys of the passed map.
+*/
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -1,14 +1,46 @@
 // Package pathlib contains every functionality for go-pathlib.
-// It's a one-file library that can be used in other projects by using Go's package system
-// or by placing the source code file itself into the source tree.
+// The platform-independent functionality lives in pathlib.go, while platform-specific
+// functionality lives in the pathlib_*.go files selected by build constraints, and the
+// Unicode tables in the generated pathlib_unicode.go. The package can be used in other
+// projects by using Go's package system or by placing all of these source files into
+// the source tree. Adapters for third-party packages live in subpackages, e.g. pflagpath.
 package pathlib
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/xml"
 	"errors"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"iter"
+	"net/url"
 	"os"
+	"os/user"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 const (
@@ -22,9 +54,24 @@ const (
 	pathCheckDir
 )
 
+const (
+	// accessRead requests read access in hasAccess.
+	accessRead uint32 = 4
+
+	// accessWrite requests write access in hasAccess.
+	accessWrite uint32 = 2
+
+	// accessExecute requests execute access in hasAccess.
+	accessExecute uint32 = 1
+)
+
 // pathSeparator is the string representation of filepath.Separator
 const pathSeparator = string(filepath.Separator)
 
+// defaultDirPerm is the permission used for implicitly created parent directories.
+// It is further restricted by the process's umask.
+const defaultDirPerm os.FileMode = 0777
+
 /*
 Path is a struct that represents a filesystem path.
 
@@ -47,6 +94,128 @@ func NewPath(path string) *Path {
 	return &Path{path: cleanPathString(path)}
 }
 
+/*
+NewPathE is a strict variant of NewPath for untrusted input. Instead of silently
+cleaning it, an error is returned if the passed path string is empty or
+whitespace only, contains null bytes or is not valid UTF-8.
+*/
+func NewPathE(path string) (*Path, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("path must not be empty")
+	}
+
+	if strings.ContainsRune(path, 0) {
+		return nil, errors.New("path must not contain null bytes")
+	}
+
+	if !utf8.ValidString(path) {
+		return nil, errors.New("path must be valid UTF-8")
+	}
+
+	return NewPath(path), nil
+}
+
+/*
+Normalization is a Unicode normalization form applied to path strings.
+*/
+type Normalization int
+
+const (
+	// NormalizationNone keeps path strings as they are.
+	NormalizationNone Normalization = iota
+
+	// NormalizationNFC applies the canonical composition, as used by most systems.
+	NormalizationNFC
+
+	// NormalizationNFD applies the canonical decomposition, as stored by macOS.
+	NormalizationNFD
+)
+
+/*
+ParseOptions configures how NewPathWithOptions parses path strings.
+*/
+type ParseOptions struct {
+
+	// TrimSpace controls whether leading and trailing whitespace is removed.
+	TrimSpace bool
+
+	// UnescapeWhitespace controls whether escaped whitespaces ("\ ") are unescaped.
+	UnescapeWhitespace bool
+
+	// BackslashSeparators controls whether backslashes are handled as path separators.
+	// It only affects operating systems whose separator is not the backslash.
+	BackslashSeparators bool
+
+	// Normalization controls which Unicode normalization form is applied.
+	Normalization Normalization
+}
+
+/*
+DefaultParseOptions returns the ParseOptions used by NewPath on this operating system.
+On Windows, whitespace is trimmed, on other operating systems, escaped whitespaces are
+unescaped and backslashes are handled as separators as well.
+*/
+func DefaultParseOptions() ParseOptions {
+	if runtime.GOOS == "windows" {
+		return ParseOptions{TrimSpace: true}
+	}
+
+	return ParseOptions{TrimSpace: true, UnescapeWhitespace: true, BackslashSeparators: true}
+}
+
+/*
+NewPathWithOptions returns a new Path, whose path string is parsed
+and cleaned according to the passed options.
+*/
+func NewPathWithOptions(path string, opts ParseOptions) *Path {
+	return &Path{path: cleanPathStringWith(path, opts)}
+}
+
+/*
+NewPathFromURI returns a new Path from a file URI as returned by AsURI, decoding its
+percent-encoding. On Windows, drive paths like "file:///C:/dir" are recognized and URIs
+with a host other than "localhost", e.g. "file://server/share/dir", are returned as UNC paths.
+An error is returned if the URI is not a file URI with an absolute path, or if it has
+a host on other operating systems, as UNC paths only exist on Windows.
+*/
+func NewPathFromURI(uri string) (*Path, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported URI scheme '%s'", u.Scheme)
+	}
+
+	if u.Opaque != "" || !strings.HasPrefix(u.Path, "/") {
+		return nil, errors.New("file URI must contain an absolute path")
+	}
+
+	if strings.ContainsRune(u.Path, 0) {
+		return nil, errors.New("path must not contain null bytes")
+	}
+
+	if u.Host != "" && !strings.EqualFold(u.Host, "localhost") {
+		if strings.Trim(u.Path, "/") == "" {
+			return nil, errors.New("UNC file URI must contain a share")
+		}
+
+		if runtime.GOOS != "windows" {
+			return nil, fmt.Errorf("file URI with host '%s' is not available: %w", u.Host, errors.ErrUnsupported)
+		}
+
+		return NewPathWithOptions(`\\`+u.Host+filepath.FromSlash(u.Path), ParseOptions{}), nil
+	}
+
+	path := u.Path
+	if runtime.GOOS == "windows" && len(path) >= 3 && isDriveLetter(path[1:3]) {
+		path = path[1:]
+	}
+
+	return NewPathWithOptions(path, ParseOptions{}), nil
+}
+
 /*
 NewCwd returns a new Path instance pointing to the application's current working directory.
 
@@ -76,591 +245,6712 @@ func NewHome() (*Path, error) {
 }
 
 /*
-PathFromParts combines passed parts into a new Path.
+NewTempRoot returns a new Path pointing to the default directory for temporary files.
+The directory is not required to exist.
+
+This function utilizes os.TempDir.
 */
-func PathFromParts(parts ...string) *Path {
-	return NewPath(".").JoinStrings(parts...)
+func NewTempRoot() *Path {
+	return NewPath(os.TempDir())
 }
 
 /*
-IsFile returns whether this Path is an existing file.
+NewExecutable returns a new Path pointing to the executable that started the current
+process. Symbolic links are resolved.
+
+This function utilizes os.Executable and filepath.EvalSymlinks.
 */
-func (p *Path) IsFile() bool {
-	return pathCheck(*p) == pathCheckFile
+func NewExecutable() (*Path, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPath(resolved), nil
 }
 
 /*
-IsDir returns whether this Path is an existing directory.
+NewExecutableDir returns a new Path pointing to the directory containing the
+executable that started the current process, e.g. to locate bundled resources.
+Symbolic links are resolved, see NewExecutable.
 */
-func (p *Path) IsDir() bool {
-	return pathCheck(*p) == pathCheckDir
+func NewExecutableDir() (*Path, error) {
+	executable, err := NewExecutable()
+	if err != nil {
+		return nil, err
+	}
+
+	return executable.Parent(), nil
+}
+
+// userDir identifies a well-known directory of the current user.
+type userDir int
+
+const (
+	userDirDesktop userDir = iota
+	userDirDocuments
+	userDirDownloads
+)
+
+// userDirs maps well-known user directories to their default name inside the
+// home directory and their key in the XDG user directories configuration.
+var userDirs = map[userDir]struct {
+	name   string
+	xdgKey string
+}{
+	userDirDesktop:   {"Desktop", "XDG_DESKTOP_DIR"},
+	userDirDocuments: {"Documents", "XDG_DOCUMENTS_DIR"},
+	userDirDownloads: {"Downloads", "XDG_DOWNLOAD_DIR"},
 }
 
 /*
-Exists returns whether this Path exists.
+NewDesktopDir returns a new Path pointing to the current user's desktop directory.
+
+On Windows, the Desktop known folder is used. On other systems except macOS, the
+XDG_DESKTOP_DIR entry of the XDG user directories configuration is respected.
+Otherwise, the directory defaults to "Desktop" inside the user's home directory.
+The directory is not required to exist.
 */
-func (p *Path) Exists() bool {
-	return pathCheck(*p) != pathCheckNoExist
+func NewDesktopDir() (*Path, error) {
+	return newUserDir(userDirDesktop)
 }
 
 /*
-Parent returns a copy of this Path in the parent directory.
-
-This function utilizes filepath.Dir.
+NewDocumentsDir returns a new Path pointing to the current user's documents directory.
+The directory is determined as described for NewDesktopDir, using the Documents known
+folder and the XDG_DOCUMENTS_DIR entry, and defaults to "Documents" inside the user's
+home directory.
 */
-func (p *Path) Parent() *Path {
-	return NewPath(filepath.Dir(p.path))
+func NewDocumentsDir() (*Path, error) {
+	return newUserDir(userDirDocuments)
 }
 
 /*
-Parts returns all single parts of the Path.
-It uses filepath.Separator to split the path string.
+NewDownloadsDir returns a new Path pointing to the current user's downloads directory.
+The directory is determined as described for NewDesktopDir, using the Downloads known
+folder and the XDG_DOWNLOAD_DIR entry, and defaults to "Downloads" inside the user's
+home directory.
 */
-func (p *Path) Parts() []string {
-	separator := pathSeparator
+func NewDownloadsDir() (*Path, error) {
+	return newUserDir(userDirDownloads)
+}
 
-	toSplit := strings.Trim(p.path, separator)
-	if toSplit == "" {
-		return []string{}
-	}
+/*
+NewAppConfigDir returns a new Path pointing to the configuration directory of the passed
+application, i.e. %AppData%\app on Windows, ~/Library/Application Support/app on macOS and
+$XDG_CONFIG_HOME/app or ~/.config/app elsewhere. The directory is not created.
 
-	return strings.Split(toSplit, separator)
+This function utilizes os.UserConfigDir.
+*/
+func NewAppConfigDir(app string) (*Path, error) {
+	return newAppDir(app, os.UserConfigDir)
 }
 
 /*
-Split splits this Path into its parent and base.
+NewAppCacheDir returns a new Path pointing to the cache directory of the passed
+application, i.e. %LocalAppData%\app on Windows, ~/Library/Caches/app on macOS and
+$XDG_CACHE_HOME/app or ~/.cache/app elsewhere. The directory is not created.
+
+This function utilizes os.UserCacheDir.
 */
-func (p *Path) Split() (*Path, string) {
-	dir, file := filepath.Split(p.path)
-	return NewPath(dir), file
+func NewAppCacheDir(app string) (*Path, error) {
+	return newAppDir(app, os.UserCacheDir)
 }
 
 /*
-Base returns the last element of this Path.
+NewAppDataDir returns a new Path pointing to the data directory of the passed
+application, i.e. %LocalAppData%\app on Windows, ~/Library/Application Support/app on
+macOS and $XDG_DATA_HOME/app or ~/.local/share/app elsewhere. The directory is not created.
+*/
+func NewAppDataDir(app string) (*Path, error) {
+	return newAppDir(app, userDataDir)
+}
 
-This function utilizes filepath.Base.
+/*
+NewPathExpandUser returns a new Path with a leading "~" or "~username" element
+expanded to the respective home directory. See ExpandUser.
 */
-func (p *Path) Base() string {
-	return filepath.Base(p.path)
+func NewPathExpandUser(path string) (*Path, error) {
+	return NewPath(path).ExpandUser()
 }
 
 /*
-Extension returns the last filename extension of this Path.
-The prefixed dot is included.
+PathFromParts combines passed parts into a new Path.
 */
-func (p *Path) Extension() string {
+func PathFromParts(parts ...string) *Path {
+	return NewPath(".").JoinStrings(parts...)
+}
 
-	base := p.Base()
+/*
+ReadFirstExisting reads the first file of the passed candidates that exists.
+It returns the file's content together with the Path that was read.
+Candidates that are not files are skipped.
 
-	if base == "." || base == ".." || base == pathSeparator {
-		return ""
+If none of the candidates exist, an error wrapping os.ErrNotExist is returned.
+*/
+func ReadFirstExisting(paths ...*Path) ([]byte, *Path, error) {
+	path, err := FirstFile(paths...)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	base = strings.TrimLeft(base, ".")
-	extension := filepath.Ext(base)
+	content, err := os.ReadFile(path.path)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return extension
+	return content, path, nil
 }
 
 /*
-Extensions returns all the Path's extensions.
-Prefixed dots are included.
+FirstExisting returns the first of the passed candidates that exists.
 
-If the file starts with a '.' (which is a common on unix
-based operating systems), the first part is ignored.
+If none of the candidates exist, an error wrapping os.ErrNotExist is returned.
 */
-func (p *Path) Extensions() []string {
+func FirstExisting(paths ...*Path) (*Path, error) {
+	return firstMatching(paths, (*Path).Exists)
+}
 
-	base := p.Base()
-	base = strings.Trim(base, ".")
-	base = strings.Trim(base, pathSeparator)
+/*
+FirstFile returns the first of the passed candidates that is an existing file.
 
-	extensions := strings.Split(base, ".")
-	if len(extensions) > 0 {
-		extensions = extensions[1:]
-	}
+If none of the candidates exist, an error wrapping os.ErrNotExist is returned.
+*/
+func FirstFile(paths ...*Path) (*Path, error) {
+	return firstMatching(paths, (*Path).IsFile)
+}
 
-	for ext := range extensions {
-		extensions[ext] = "." + extensions[ext]
-	}
+/*
+FirstDir returns the first of the passed candidates that is an existing directory.
 
-	return extensions
+If none of the candidates exist, an error wrapping os.ErrNotExist is returned.
+*/
+func FirstDir(paths ...*Path) (*Path, error) {
+	return firstMatching(paths, (*Path).IsDir)
 }
 
 /*
-Stem returns the last element of this Path without the extension.
+ExpandTemplate replaces all tokens in the passed template and returns the result as a new Path.
+A token is a name enclosed in curly braces, e.g. "logs/{date}/{host}/{name}.log".
+Literal braces are written as "{{" and "}}".
+
+Tokens are looked up in vars first. The following built-in tokens are supported otherwise:
+  - date: the current date formatted as "2006-01-02"
+  - time: the current time formatted as "15-04-05"
+  - date:LAYOUT, time:LAYOUT: the current time formatted using a custom time.Layout
+  - unix: the current unix timestamp in seconds
+  - pid: the current process id
+  - host, hostname: the machine's hostname
+  - uuid: a random version 4 UUID
+
+An error is returned for unknown tokens and malformed templates.
 */
-func (p *Path) Stem() string {
-	base := p.Base()
-
-	// stem definitions
-	if base == "." || base == pathSeparator {
-		return ""
-	}
-
-	if base == ".." {
-		return ".."
+func ExpandTemplate(template string, vars map[string]string) (*Path, error) {
+	expanded, err := expandTemplate(template, vars, time.Now())
+	if err != nil {
+		return nil, err
 	}
 
-	// in case stem has
-
-	return base[:len(base)-len(p.Extension())]
+	return NewPath(expanded), nil
 }
 
 /*
-MinimalStem returns the last element of this Path without all extensions.
+GroupBy groups the passed paths by the key returned from the key function.
+The order of paths within a group is preserved.
 */
-func (p *Path) MinimalStem() string {
-	base := p.Base()
-
-	if base == "." || base == pathSeparator {
-		return ""
+func GroupBy(paths []*Path, key func(*Path) string) map[string][]*Path {
+	groups := make(map[string][]*Path)
+	for _, path := range paths {
+		k := key(path)
+		groups[k] = append(groups[k], path)
 	}
 
-	return base[:len(base)-len(strings.Join(p.Extensions(), ""))]
+	return groups
 }
 
 /*
-Root returns the first part of the path.
-On absolute paths this is the filesystem root, on relative paths all parts
-up to the first non-'..' part are included.
-
-On Unix-based operating systems, the Windows path root (e.g. 'C:\')
-is not considered a filepath root. However, it will be returned as a root
-because 'C:\' or 'C:/' is seen as the root of a relative path.
+GroupByParent groups the passed paths by their parent directory.
+The map keys are the unescaped parent path strings.
 */
-func (p *Path) Root() string {
+func GroupByParent(paths []*Path) map[string][]*Path {
+	return GroupBy(paths, func(p *Path) string {
+		return p.Parent().path
+	})
+}
 
-	if p.IsRelative() {
-		parts := p.Parts()
-		var rootParts []string
+/*
+GroupByExtension groups the passed paths by their last extension, including the dot.
+Paths without an extension are grouped under the empty string.
+*/
+func GroupByExtension(paths []*Path) map[string][]*Path {
+	return GroupBy(paths, (*Path).Extension)
+}
 
-		for _, part := range parts {
-			rootParts = append(rootParts, part)
-			if part != ".." {
-				break
-			}
+/*
+Partition splits the passed paths into the ones the predicate returns true for
+and the ones it returns false for. The order of paths is preserved.
+*/
+func Partition(paths []*Path, predicate func(*Path) bool) ([]*Path, []*Path) {
+	var matching, other []*Path
+	for _, path := range paths {
+		if predicate(path) {
+			matching = append(matching, path)
+		} else {
+			other = append(other, path)
 		}
-
-		return filepath.Join(rootParts...)
-	}
-
-	pathStr := p.path
-
-	if pathStr == pathSeparator {
-		return pathStr
-	}
-
-	root := strings.SplitN(p.path, pathSeparator, 2)[0]
-
-	if root == "" {
-		return pathSeparator
 	}
 
-	return root
+	return matching, other
 }
 
 /*
-IsAbsolute returns whether this Path is absolute.
-
-On non-Windows operating systems, the Windows path root (e.g. 'C:\')
-is not considered a file root but as a regular (relative) path element.
-Thus, this function would return false.
-
-This function utilizes filepath.IsAbs.
+IsFile returns whether this Path is an existing file.
 */
-func (p *Path) IsAbsolute() bool {
-	return filepath.IsAbs(p.path)
+func (p *Path) IsFile() bool {
+	return pathCheck(*p) == pathCheckFile
 }
 
 /*
-IsRelative returns whether this Path is relative.
+IsDir returns whether this Path is an existing directory.
+*/
+func (p *Path) IsDir() bool {
+	return pathCheck(*p) == pathCheckDir
+}
 
-This function returns the inverse of IsAbsolute.
+/*
+Exists returns whether this Path exists.
 */
-func (p *Path) IsRelative() bool {
-	return !p.IsAbsolute()
+func (p *Path) Exists() bool {
+	return pathCheck(*p) != pathCheckNoExist
 }
 
 /*
-RelativeTo returns this Path relative to another.
+LExists returns whether this Path exists, without following symbolic links.
+In contrast to Exists, it returns true for broken symbolic links.
 
-This function utilizes filepath.Rel.
+This function utilizes os.Lstat.
 */
-func (p *Path) RelativeTo(o *Path) (*Path, error) {
-	rp, err := filepath.Rel(o.path, p.path)
-	return NewPath(rp), err
+func (p *Path) LExists() bool {
+	_, err := os.Lstat(p.path)
+	return err == nil
 }
 
 /*
-Absolute returns an absolute representation of this Path.
-If the Path is relative, it will be joined with the current working directory.
+IsSymlink returns whether this Path is a symbolic link.
+The link's target does not need to exist.
 
-This function utilizes filepath.Abs.
+This function utilizes os.Lstat.
 */
-func (p *Path) Absolute() (*Path, error) {
-	ap, err := filepath.Abs(p.path)
-	return NewPath(ap), err
+func (p *Path) IsSymlink() bool {
+	info, err := os.Lstat(p.path)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeSymlink != 0
 }
 
 /*
-AbsoluteTo returns an absolute representation of this Path towards another.
-If the Path is relative, it will be joined with the provided Path, else this Path is returned.
-
-Requires the other Path to be absolute.
+PathInfo contains information about a filesystem entry.
+It is returned by Path's Stat and Lstat.
 */
-func (p *Path) AbsoluteTo(o *Path) (*Path, error) {
+type PathInfo struct {
 
-	// if path is already absolute, ignore it
-	if p.IsAbsolute() {
-		return p, nil
-	}
+	// Name is the base name of the entry.
+	Name string
 
-	if o.IsRelative() {
-		return nil, errors.New("other path must be absolute")
-	}
+	// Size is the length in bytes for regular files and system-dependent for others.
+	Size int64
 
-	return o.Join(p), nil
+	// Mode contains the entry's type and permission bits.
+	Mode os.FileMode
+
+	// ModTime is the last modification time.
+	ModTime time.Time
+
+	// Uid is the numeric id of the owning user, or -1 if it is not available.
+	Uid int
+
+	// Gid is the numeric id of the owning group, or -1 if it is not available.
+	Gid int
 }
 
 /*
-Resolve resolves all symbolic links. If this Path is relative,
-the result will be relative to the current directory, unless
-one of the components is an absolute symbolic link.
+IsDir returns whether the entry is a directory.
+*/
+func (i PathInfo) IsDir() bool {
+	return i.Mode.IsDir()
+}
 
-Resolve requires this Path to exist.
+/*
+IsRegular returns whether the entry is a regular file.
+*/
+func (i PathInfo) IsRegular() bool {
+	return i.Mode.IsRegular()
+}
 
-This function utilizes filepath.EvalSymlinks.
+/*
+Stat returns information about the filesystem entry of this Path.
+Symbolic links are followed.
+
+This function utilizes os.Stat.
 */
-func (p *Path) Resolve() (*Path, error) {
-	if !p.Exists() {
-		return nil, errors.New("this path does not exist")
+func (p *Path) Stat() (PathInfo, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return PathInfo{}, err
 	}
 
-	ep, err := filepath.EvalSymlinks(p.path)
+	return newPathInfo(info), nil
+}
+
+/*
+Lstat returns information about the filesystem entry of this Path.
+In contrast to Stat, symbolic links are not followed and
+the information describes the link itself.
+
+This function utilizes os.Lstat.
+*/
+func (p *Path) Lstat() (PathInfo, error) {
+	info, err := os.Lstat(p.path)
 	if err != nil {
-		return nil, err
+		return PathInfo{}, err
 	}
 
-	return NewPath(ep), nil
+	return newPathInfo(info), nil
 }
 
 /*
-Join returns a new Path with all passed Path structs joined together.
-Use JoinStrings to join strings with this Path.
+Size returns the size of this file in bytes. Symbolic links are followed.
 
-This function utilizes filepath.Join.
+An error is returned for directories, because their size is system-dependent.
 */
-func (p *Path) Join(paths ...*Path) *Path {
-	pathsStr := make([]string, len(paths))
-	for i, path := range paths {
-		pathsStr[i] = path.path
+func (p *Path) Size() (int64, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return 0, err
 	}
 
-	return NewPath(filepath.Join(append([]string{p.path}, pathsStr...)...))
+	if info.IsDir() {
+		return 0, errors.New("this path is a directory")
+	}
+
+	return info.Size(), nil
 }
 
 /*
-JoinStrings returns a new Path with all passed strings joined together.
-
-This function utilizes filepath.Join.
+ModTime returns the last modification time of this Path.
+Symbolic links are followed.
 */
-func (p *Path) JoinStrings(paths ...string) *Path {
-	return NewPath(filepath.Join(append([]string{p.path}, paths...)...))
+func (p *Path) ModTime() (time.Time, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
 }
 
 /*
-Glob returns all paths matching the given pattern within this Path's directory.
+AccessTime returns the last access time of this Path. Symbolic links are followed.
 
-This function utilizes filepath.Glob. It ignores IO errors.
+This is a best-effort function, as access times depend on the platform and
+filesystem mount options. An error wrapping errors.ErrUnsupported is returned
+on platforms that do not provide access times.
 */
-func (p *Path) Glob(pattern string) ([]*Path, error) {
-	matches, err := nativeGlob(p, pattern)
+func (p *Path) AccessTime() (time.Time, error) {
+	info, err := os.Stat(p.path)
 	if err != nil {
-		return nil, err
+		return time.Time{}, err
 	}
 
-	paths := make([]*Path, len(matches))
-	for idx, match := range matches {
-		paths[idx] = NewPath(match)
+	accessTime, ok := fileAccessTime(info)
+	if !ok {
+		return time.Time{}, fmt.Errorf("access time is not available: %w", errors.ErrUnsupported)
 	}
 
-	return paths, nil
+	return accessTime, nil
 }
 
 /*
-Contains returns whether the passed pattern exist within this Path's directory.
+ChangeTime returns the last status change time of this Path,
+i.e. the last time its content or metadata changed. Symbolic links are followed.
 
-This function utilizes filepath.Glob.
+This is a best-effort function. An error wrapping errors.ErrUnsupported is
+returned on platforms that do not provide change times, e.g. on Windows.
 */
-func (p *Path) Contains(pattern string) (bool, error) {
-	matches, err := nativeGlob(p, pattern)
+func (p *Path) ChangeTime() (time.Time, error) {
+	info, err := os.Stat(p.path)
 	if err != nil {
-		return false, err
+		return time.Time{}, err
 	}
 
-	return len(matches) != 0, nil
+	changeTime, ok := fileChangeTime(info)
+	if !ok {
+		return time.Time{}, fmt.Errorf("change time is not available: %w", errors.ErrUnsupported)
+	}
+
+	return changeTime, nil
 }
 
 /*
-BContains returns whether the passed pattern exists within this Path's directory.
-It wraps Contains and returns the boolean success value.
+Chmod changes the mode of this Path. Symbolic links are followed.
+
+This function utilizes os.Chmod.
 */
-func (p *Path) BContains(pattern string) bool {
-	contains, _ := p.Contains(pattern)
-	return contains
+func (p *Path) Chmod(mode os.FileMode) error {
+	return os.Chmod(p.path, mode)
 }
 
 /*
-IsCaseSensitiveFs returns whether a given path is on a case-sensitive filesystem.
+ChmodRecursive changes the mode of this Path and all its children.
+Directories receive dirMode, all other entries receive fileMode.
+Symbolic links within the tree are skipped.
 
-Currently, this function checks the sensitivity using the path's base.
+The modes of directories are changed after their children,
+so that restrictive directory modes do not prevent the traversal.
 */
-func IsCaseSensitiveFs(p *Path) (bool, error) {
-	// IMPORTANT:
-	// It would make sense to check if this Path actually exists before
-	// continuing the check. But this does not make sense in the context
-	// of this function's goal.
+func (p *Path) ChmodRecursive(fileMode os.FileMode, dirMode os.FileMode) error {
+	var dirs []string
 
-	// TODO Check sensitivity of parent parts --> underlying fs may have mounted
-	// 	multiple filesystems that are switching sensitivity.
+	err := filepath.WalkDir(p.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 
-	alt := p.Parent()
-	alt = alt.JoinStrings(flipCase(p.Base()))
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
 
-	// get file stat for passed file (required for later comparison in os.SameFile)
-	pathInfo, err := os.Stat(p.path)
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		return os.Chmod(path, fileMode)
+	})
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	// if file does not exist, assume to be on case-sensitive filesystem
-	altInfo, err := os.Stat(alt.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return true, nil
+	for i := len(dirs) - 1; i >= 0; i-- {
+		err = os.Chmod(dirs[i], dirMode)
+		if err != nil {
+			return err
 		}
-
-		return false, err
 	}
 
-	// if both file exist, check if they are the same
-	// if they are equal, then the filesystem is case-insensitive,
-	// else the filesystem is case-sensitive
-	return !os.SameFile(pathInfo, altInfo), nil
+	return nil
 }
 
 /*
-Equals returns whether this and another Path are structurally the same.
-It respects case sensitivity.
+Chtimes changes the access and modification times of this Path.
+A zero time.Time value leaves the respective time unchanged. Symbolic links are followed.
+
+This function utilizes os.Chtimes.
 */
-func (p *Path) Equals(other *Path) bool {
-	return p.path == other.path
+func (p *Path) Chtimes(atime time.Time, mtime time.Time) error {
+	return os.Chtimes(p.path, atime, mtime)
 }
 
 /*
-EqualsString returns whether the passed string matches this Path.
+Chown changes the numeric user and group id of this Path.
+A value of -1 leaves the respective id unchanged. Symbolic links are followed.
 
-This function converts the passed string to a Path object and calls Equals.
+This function utilizes os.Chown.
 */
-func (p *Path) EqualsString(other string) bool {
-	return p.path == cleanPathString(other)
+func (p *Path) Chown(uid int, gid int) error {
+	return os.Chown(p.path, uid, gid)
 }
 
 /*
-EqualsCi returns whether this and another Path are structurally the same.
-It ignores case sensitivity.
-*/
-func (p *Path) EqualsCi(other *Path) bool {
-	return equalsStringCaseInsensitive(p.String(), other.String())
-}
+Owner returns the name of the user owning this Path. Symbolic links are followed.
 
-/*
-EqualsStringCi returns whether the passed string matches this Path.
-it ignores case sensitivity.
+An error wrapping errors.ErrUnsupported is returned on platforms
+that do not provide numeric ownership, e.g. on Windows.
+
+This function utilizes user.LookupId.
 */
-func (p *Path) EqualsStringCi(other string) bool {
-	return equalsStringCaseInsensitive(p.String(), other)
+func (p *Path) Owner() (string, error) {
+	info, err := p.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if info.Uid == -1 {
+		return "", fmt.Errorf("file ownership is not available: %w", errors.ErrUnsupported)
+	}
+
+	owner, err := user.LookupId(strconv.Itoa(info.Uid))
+	if err != nil {
+		return "", err
+	}
+
+	return owner.Username, nil
 }
 
 /*
-EqualsFS returns whether this and another Path are the same on the filesystem.
-The evaluation also considers filesystem case sensitivity.
+Group returns the name of the group owning this Path. Symbolic links are followed.
+
+An error wrapping errors.ErrUnsupported is returned on platforms
+that do not provide numeric ownership, e.g. on Windows.
+
+This function utilizes user.LookupGroupId.
 */
-func (p *Path) EqualsFS(other *Path) bool {
-	structurallyIdentical := equalsStringCaseInsensitive(p.String(), other.String())
-	if !structurallyIdentical {
-		return false
+func (p *Path) Group() (string, error) {
+	info, err := p.Stat()
+	if err != nil {
+		return "", err
 	}
 
-	// if equal in lowercase, proceed to check if path is on a
-	// case-sensitive filesystem or not
-	caseSensitive, err := IsCaseSensitiveFs(p)
-	if err != nil {
-		// return false in case of an error
-		return false
+	if info.Gid == -1 {
+		return "", fmt.Errorf("file ownership is not available: %w", errors.ErrUnsupported)
 	}
 
-	// if case-sensitive, compare both original strings
-	if caseSensitive {
-		return p.path == other.path
+	group, err := user.LookupGroupId(strconv.Itoa(info.Gid))
+	if err != nil {
+		return "", err
 	}
 
-	// if case-insensitive, return true
-	return true
+	return group.Name, nil
 }
 
 /*
-ToPosix returns a string representation with forward slashes.
+IsReadable returns whether the current process may read this Path.
+
+On Unix-based operating systems, this is checked using the access system call.
+On Linux, the effective user and group ids are checked, elsewhere the real ones.
+On Windows, every existing Path is considered readable.
 */
-func (p *Path) ToPosix() string {
-	return filepath.ToSlash(p.String())
+func (p *Path) IsReadable() bool {
+	return hasAccess(p.path, accessRead)
 }
 
 /*
-WithName returns this Path but with another base.
+IsWritable returns whether the current process may write to this Path.
+
+On Unix-based operating systems, this is checked using the access system call.
+On Linux, the effective user and group ids are checked, elsewhere the real ones.
+On Windows, every existing Path without the read-only attribute is considered writable.
 */
-func (p *Path) WithName(name string) *Path {
-	return p.Parent().JoinStrings(name)
+func (p *Path) IsWritable() bool {
+	return hasAccess(p.path, accessWrite)
 }
 
 /*
-Copy creates a copy of this Path.
+IsExecutable returns whether the current process may execute this Path.
+For directories, this means that they may be traversed.
 
-Fresh out of the oven, just for you.
+On Unix-based operating systems, this is checked using the access system call.
+On Linux, the effective user and group ids are checked, elsewhere the real ones.
+On Windows, directories and files with an extension listed in PATHEXT are considered executable.
 */
-func (p *Path) Copy() *Path {
-	return NewPath(p.path)
+func (p *Path) IsExecutable() bool {
+	return hasAccess(p.path, accessExecute)
 }
 
 /*
-String returns this Path as a string.
-*/
-func (p *Path) String() string {
-	pathStr := p.path
-
-	// re-add removed whitespace escape characters
-	if runtime.GOOS != "windows" {
-		pathStr = strings.ReplaceAll(pathStr, " ", "\\ ")
-	}
+IsHidden returns whether this Path is hidden.
 
-	return pathStr
+On Unix-based operating systems, Paths whose base starts with a dot are hidden.
+This check is purely lexical. On Windows, the hidden file attribute is checked,
+which requires this Path to exist.
+*/
+func (p *Path) IsHidden() (bool, error) {
+	return isHidden(p.path)
 }
 
 /*
-UnmarshalText unmarshalls any byte array into a Path type.
-Implements the encoding.TextUnmarshaler interface.
+TreeSizeOptions configures the calculation of TreeSize.
 */
-func (p *Path) UnmarshalText(text []byte) error {
-	*p = *NewPath(string(text))
-	return nil
+type TreeSizeOptions struct {
+
+	// FollowSymlinks controls whether symbolic links are followed. Every
+	// directory is only counted once, even if multiple links point to it.
+	FollowSymlinks bool
+
+	// DiskUsage controls whether the allocated on-disk size is counted instead
+	// of the apparent size. This includes the size of directories. On platforms
+	// that do not provide the allocated size, the apparent size is counted.
+	DiskUsage bool
 }
 
 /*
-MarshalText marshals this Path into a byte array.
-Implements the encoding.TextMarshaler interface.
+TreeSize returns the sum of all file sizes within this Path's directory tree.
+If this Path is a file, its size is returned.
+If this Path is a symbolic link, it is always resolved, regardless of opts.FollowSymlinks.
 */
-func (p *Path) MarshalText() (text []byte, err error) {
-	return []byte(p.String()), nil
+func (p *Path) TreeSize(opts TreeSizeOptions) (int64, error) {
+	root := p.path
+
+	// a symbolic link as root is resolved, so that the tree it points to is measured
+	info, err := os.Lstat(root)
+	if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		root, err = filepath.EvalSymlinks(root)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return treeSize(root, opts, make(map[string]struct{}))
 }
 
 /*
-clean cleans up this Path.
-
-This function utilizes filepath.Clean.
+TreeStats contains statistics about a directory tree.
+It is returned by Path's TreeStats.
 */
-func cleanPathString(p string) string {
+type TreeStats struct {
 
-	dirty := strings.TrimSpace(p)
+	// Files is the number of regular files.
+	Files int
 
-	// on non-windows operating systems
-	if runtime.GOOS != "windows" {
-		// remove whitespace escape characters during internal representation
-		dirty = strings.ReplaceAll(dirty, "\\ ", " ")
+	// Dirs is the number of directories, excluding the root.
+	Dirs int
 
-		// replace all other '\\' characters with separator
-		dirty = strings.ReplaceAll(dirty, "\\", pathSeparator)
-	}
+	// Symlinks is the number of symbolic links.
+	Symlinks int
 
-	cleanPath := filepath.Clean(dirty)
-	return cleanPath
+	// Other is the number of other entries, e.g. devices, sockets or named pipes.
+	Other int
+
+	// Bytes is the total size of all regular files.
+	Bytes int64
 }
 
 /*
-pathCheck is a lower level Path existence checker.
-It returns 0 if the path does not exist, 2 if it's a file and 2 if it's a directory.
+TreeStats returns statistics about all entries within this Path's directory tree.
+Symbolic links are counted, but not followed.
 */
-func pathCheck(p Path) int {
-	fileInfo, err := os.Stat(p.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return pathCheckNoExist
+func (p *Path) TreeStats() (TreeStats, error) {
+	stats := TreeStats{}
+
+	err := filepath.WalkDir(p.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-	}
 
-	if fileInfo == nil {
-		return pathCheckNoExist
-	}
+		switch {
+		case path == p.path && d.IsDir():
+			// the root is not counted
+		case d.IsDir():
+			stats.Dirs++
+		case d.Type()&fs.ModeSymlink != 0:
+			stats.Symlinks++
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
 
-	if fileInfo.IsDir() {
-		return pathCheckDir
+			stats.Files++
+			stats.Bytes += info.Size()
+		default:
+			stats.Other++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return TreeStats{}, err
 	}
 
-	return pathCheckFile
+	return stats, nil
 }
 
 /*
-flipCase is a utility function that takes the first character
-and flips it's case. The leftover characters are appended.
-This results in a string which is different from the original which can be used for
-e.g. case sensitivity (in)variance.
+DeviceID returns the identifier of the device this Path resides on.
+Two Paths with the same device identifier are on the same filesystem.
+Symbolic links are followed.
+
+On Windows, the volume serial number is returned. An error wrapping
+errors.ErrUnsupported is returned on platforms without device identifiers.
 */
-func flipCase(s string) string {
-	if s == "" {
-		return s
-	}
-	firstChar := string(s[0])
-	if strings.ToLower(firstChar) == firstChar {
-		return strings.ToUpper(firstChar) + s[1:]
-	}
-	return strings.ToLower(firstChar) + s[1:]
+func (p *Path) DeviceID() (uint64, error) {
+	dev, _, err := fileID(p.path)
+	return dev, err
 }
 
 /*
-nativeGlob is a wrapper function for Go's filepath.Glob.
-It checks if the passed Path exists and returns the raw matches or errors.
+Inode returns the inode number of this Path, which identifies it on its device.
+Symbolic links are followed.
 
-Returns an error if pattern is an empty string.
-
-filepath.Glob ignores IO errors.
+On Windows, the file index is returned. An error wrapping
+errors.ErrUnsupported is returned on platforms without inode numbers.
 */
-func nativeGlob(p *Path, pattern string) ([]string, error) {
-	if strings.TrimSpace(pattern) == "" {
-		return nil, errors.New("pattern must not be empty")
-	}
+func (p *Path) Inode() (uint64, error) {
+	_, ino, err := fileID(p.path)
+	return ino, err
+}
+
+/*
+NLinks returns the number of hard links pointing to this Path's file.
+Symbolic links are followed.
+
+An error wrapping errors.ErrUnsupported is returned on platforms without link counts.
+*/
+func (p *Path) NLinks() (uint64, error) {
+	return fileLinkCount(p.path)
+}
+
+/*
+DiskUsage contains the capacity of a filesystem in bytes.
+It is returned by Path's DiskUsage.
+*/
+type DiskUsage struct {
+
+	// Total is the total size of the filesystem.
+	Total uint64
+
+	// Used is the number of used bytes.
+	Used uint64
+
+	// Available is the number of bytes available to unprivileged users.
+	// It may be less than Total minus Used due to reserved blocks.
+	Available uint64
+}
+
+/*
+DiskUsage returns the capacity of the filesystem containing this Path.
+
+It utilizes statfs on Unix-based operating systems and GetDiskFreeSpaceEx on Windows.
+An error wrapping errors.ErrUnsupported is returned on other platforms.
+*/
+func (p *Path) DiskUsage() (DiskUsage, error) {
+	return diskUsage(p.path)
+}
+
+/*
+IsFifo returns whether this Path is an existing named pipe (FIFO).
+Symbolic links are followed.
+*/
+func (p *Path) IsFifo() bool {
+	return hasModeType(p, os.ModeNamedPipe)
+}
+
+/*
+IsSocket returns whether this Path is an existing Unix domain socket.
+Symbolic links are followed.
+*/
+func (p *Path) IsSocket() bool {
+	return hasModeType(p, os.ModeSocket)
+}
+
+/*
+IsBlockDevice returns whether this Path is an existing block device.
+Symbolic links are followed.
+*/
+func (p *Path) IsBlockDevice() bool {
+	return hasModeType(p, os.ModeDevice) && !hasModeType(p, os.ModeCharDevice)
+}
+
+/*
+IsCharDevice returns whether this Path is an existing character device.
+Symbolic links are followed.
+*/
+func (p *Path) IsCharDevice() bool {
+	return hasModeType(p, os.ModeCharDevice)
+}
+
+/*
+IsJunction returns whether this Path is an NTFS junction point.
+Junctions are distinct from symbolic links and only exist on Windows.
+On other operating systems, false is returned for every existing Path.
+
+An error is returned if this Path does not exist.
+*/
+func (p *Path) IsJunction() (bool, error) {
+	return isJunction(p.path)
+}
+
+/*
+Parent returns a copy of this Path in the parent directory.
+
+This function utilizes filepath.Dir.
+*/
+func (p *Path) Parent() *Path {
+	return NewPath(filepath.Dir(p.path))
+}
+
+/*
+Parents returns all logical ancestors of this Path, starting with the direct parent.
+Relative Paths end with ".", absolute Paths with the root. The root
+and "." have no parents, thus an empty slice is returned for them.
+*/
+func (p *Path) Parents() []*Path {
+	parents := []*Path{}
+
+	current := p.path
+	for {
+		parent := filepath.Dir(current)
+		if parent == current {
+			return parents
+		}
+
+		parents = append(parents, NewPath(parent))
+		current = parent
+	}
+}
+
+/*
+Parts returns all single parts of the Path.
+It uses filepath.Separator to split the path string.
+*/
+func (p *Path) Parts() []string {
+	separator := pathSeparator
+
+	toSplit := strings.Trim(p.path, separator)
+	if toSplit == "" {
+		return []string{}
+	}
+
+	return strings.Split(toSplit, separator)
+}
+
+/*
+Depth returns the number of elements of this Path, excluding the root
+of absolute Paths and Windows volume names. Thus, the root and "." have depth 0,
+"/foo/bar" and "foo/bar" have depth 2. Leading ".." elements count as regular elements,
+so that "../foo" has depth 2.
+*/
+func (p *Path) Depth() int {
+	return len(pathElements(p.path[len(filepath.VolumeName(p.path)):]))
+}
+
+/*
+Split splits this Path into its parent and base.
+*/
+func (p *Path) Split() (*Path, string) {
+	dir, file := filepath.Split(p.path)
+	return NewPath(dir), file
+}
+
+/*
+Base returns the last element of this Path.
+
+This function utilizes filepath.Base.
+*/
+func (p *Path) Base() string {
+	return filepath.Base(p.path)
+}
+
+/*
+Extension returns the last filename extension of this Path.
+The prefixed dot is included.
+*/
+func (p *Path) Extension() string {
+
+	base := p.Base()
+
+	if base == "." || base == ".." || base == pathSeparator {
+		return ""
+	}
+
+	base = strings.TrimLeft(base, ".")
+	extension := filepath.Ext(base)
+
+	return extension
+}
+
+/*
+Extensions returns all the Path's extensions.
+Prefixed dots are included.
+
+If the file starts with a '.' (which is a common on unix
+based operating systems), the first part is ignored.
+*/
+func (p *Path) Extensions() []string {
+
+	base := p.Base()
+	base = strings.Trim(base, ".")
+	base = strings.Trim(base, pathSeparator)
+
+	extensions := strings.Split(base, ".")
+	if len(extensions) > 0 {
+		extensions = extensions[1:]
+	}
+
+	for ext := range extensions {
+		extensions[ext] = "." + extensions[ext]
+	}
+
+	return extensions
+}
+
+/*
+Stem returns the last element of this Path without the extension.
+*/
+func (p *Path) Stem() string {
+	base := p.Base()
+
+	// stem definitions
+	if base == "." || base == pathSeparator {
+		return ""
+	}
+
+	if base == ".." {
+		return ".."
+	}
+
+	// in case stem has
+
+	return base[:len(base)-len(p.Extension())]
+}
+
+/*
+MinimalStem returns the last element of this Path without all extensions.
+*/
+func (p *Path) MinimalStem() string {
+	base := p.Base()
+
+	if base == "." || base == pathSeparator {
+		return ""
+	}
+
+	return base[:len(base)-len(strings.Join(p.Extensions(), ""))]
+}
+
+/*
+Root returns the first part of the path.
+On absolute paths this is the filesystem root, on relative paths all parts
+up to the first non-'..' part are included.
+
+On Unix-based operating systems, the Windows path root (e.g. 'C:\')
+is not considered a filepath root. However, it will be returned as a root
+because 'C:\' or 'C:/' is seen as the root of a relative path.
+*/
+func (p *Path) Root() string {
+
+	if p.IsRelative() {
+		parts := p.Parts()
+		var rootParts []string
+
+		for _, part := range parts {
+			rootParts = append(rootParts, part)
+			if part != ".." {
+				break
+			}
+		}
+
+		return filepath.Join(rootParts...)
+	}
+
+	pathStr := p.path
+
+	if pathStr == pathSeparator {
+		return pathStr
+	}
+
+	root := strings.SplitN(p.path, pathSeparator, 2)[0]
+
+	if root == "" {
+		return pathSeparator
+	}
+
+	return root
+}
+
+/*
+Drive returns the drive letter or UNC share of this Path on Windows,
+e.g. "C:" or "\\server\share". On other operating systems, it is always empty.
+
+This function utilizes filepath.VolumeName.
+*/
+func (p *Path) Drive() string {
+	return filepath.VolumeName(p.path)
+}
+
+/*
+Anchor returns the drive and root of this Path combined, e.g. "C:\" or "/".
+It is empty for relative Paths, except for Windows paths relative to a drive like "C:foo".
+*/
+func (p *Path) Anchor() string {
+	anchor := p.Drive()
+	if p.IsAbsolute() {
+		anchor += pathSeparator
+	}
+
+	return anchor
+}
+
+/*
+UNCComponents returns the server and share of a UNC path like "\\server\share\dir".
+UNC paths only exist on Windows. On other operating systems, a leading double
+separator is collapsed like any other, thus false is always returned.
+*/
+func (p *Path) UNCComponents() (server string, share string, ok bool) {
+	if runtime.GOOS != "windows" {
+		return "", "", false
+	}
+
+	server, share, _, ok = splitUNC(p.path)
+	return server, share, ok
+}
+
+/*
+ToExtendedLength returns this Path in the Windows extended-length form,
+e.g. "\\?\C:\dir" or "\\?\UNC\server\share\dir", which is not limited to MAX_PATH
+characters. Relative Paths are made absolute first. Paths already in this form,
+and all Paths on other operating systems, are returned unchanged.
+
+Note that the os package already applies this form transparently for long absolute paths,
+so this is mainly required when passing paths to other programs or system calls.
+*/
+func (p *Path) ToExtendedLength() *Path {
+	if runtime.GOOS != "windows" || strings.HasPrefix(p.path, `\\?\`) {
+		return p.Copy()
+	}
+
+	absolute, err := filepath.Abs(p.path)
+	if err != nil {
+		return p.Copy()
+	}
+
+	if server, share, rest, ok := splitUNC(absolute); ok {
+		return NewPath(`\\?\UNC\` + server + `\` + share + `\` + rest)
+	}
+
+	return NewPath(`\\?\` + absolute)
+}
+
+// longPathPrefixing controls whether long paths are passed in extended-length form, see SetLongPathPrefixing.
+var longPathPrefixing atomic.Bool
+
+/*
+SetLongPathPrefixing controls whether paths reaching MAX_PATH are automatically converted
+into the extended-length form as by ToExtendedLength, before they are passed to Windows
+system calls. Prefixing is disabled by default, applies to all Paths and has no effect
+on other operating systems.
+
+Operations using the os package are not affected, as it already applies this form
+transparently. Prefixing affects the operations that call the Windows API directly,
+like IsHidden, IsJunction, DeviceID, Inode, NLinks and DiskUsage.
+*/
+func SetLongPathPrefixing(enabled bool) {
+	longPathPrefixing.Store(enabled)
+}
+
+/*
+systemPath returns the passed path as it is passed to system calls,
+which is its extended-length form on Windows if SetLongPathPrefixing is enabled
+and the path is too long for the regular form.
+*/
+func systemPath(path string) string {
+	// directories are limited to MAX_PATH minus 12 characters for an 8.3 file name,
+	// and byte lengths are never shorter than UTF-16 lengths
+	if runtime.GOOS != "windows" || !longPathPrefixing.Load() || len(path) < 248 {
+		return path
+	}
+
+	return NewPath(path).ToExtendedLength().path
+}
+
+/*
+IsReserved returns whether the name of this Path is reserved on Windows,
+like CON, PRN, AUX, NUL, COM1 to COM9 and LPT1 to LPT9, case-insensitively
+and including any extensions, e.g. "nul.txt". The check is performed
+on all operating systems to allow rejecting names before creating them.
+*/
+func (p *Path) IsReserved() bool {
+	if !p.hasName() {
+		return false
+	}
+
+	return isReservedName(p.Base())
+}
+
+/*
+OS is a target operating system for validating Paths, named as in runtime.GOOS.
+Operating systems other than Windows are validated using POSIX rules.
+*/
+type OS string
+
+const (
+	// OSWindows targets Windows.
+	OSWindows OS = "windows"
+
+	// OSLinux targets Linux.
+	OSLinux OS = "linux"
+
+	// OSDarwin targets macOS.
+	OSDarwin OS = "darwin"
+)
+
+/*
+CurrentOS returns the operating system this program is running on.
+*/
+func CurrentOS() OS {
+	return OS(runtime.GOOS)
+}
+
+/*
+Validate returns an error describing all elements of this Path that are not valid
+file names on the target operating system. Null bytes and elements longer than
+255 bytes, or 255 UTF-16 code units on Windows, are invalid everywhere. On Windows, the characters <>:"/\|?* and control
+characters, reserved names (see IsReserved) and trailing dots or spaces are invalid as well.
+*/
+func (p *Path) Validate(target OS) error {
+	elements := pathElements(p.path[len(filepath.VolumeName(p.path)):])
+
+	// drive letters are part of the elements when validating on another operating system
+	if target == OSWindows && len(elements) > 0 && isDriveLetter(elements[0]) {
+		elements = elements[1:]
+	}
+
+	var errs []error
+	for _, element := range elements {
+		if element == "." || element == ".." {
+			continue
+		}
+
+		err := validateElement(element, target)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+CheckLengthLimits returns an error if this Path exceeds the length limits of
+the target operating system. Paths must be shorter than MAX_PATH (260 UTF-16 code units)
+on Windows, unless in extended-length form like "\\?\C:\dir", 1024 bytes on macOS and 4096 bytes otherwise.
+Elements must not be longer than 255 bytes, or 255 UTF-16 code units on Windows.
+
+Relative Paths are checked as they are, although the limits apply to their absolute form.
+*/
+func (p *Path) CheckLengthLimits(target OS) error {
+	length := len(p.path)
+	limit := 4096
+
+	switch target {
+	case OSWindows:
+		length = len(utf16.Encode([]rune(p.path)))
+		limit = 260
+		if isExtendedLength(p.path) {
+			limit = 32767
+		}
+	case OSDarwin:
+		limit = 1024
+	}
+
+	// the limits include the terminating null character
+	if length >= limit {
+		return fmt.Errorf("path is %d long, but must be shorter than %d", length, limit)
+	}
+
+	for _, element := range pathElements(p.path[len(filepath.VolumeName(p.path)):]) {
+		if elementLength := elementLength(element, target); elementLength > maxElementLength {
+			return fmt.Errorf("element '%s' is %d long, but must not be longer than %d", element, elementLength, maxElementLength)
+		}
+	}
+
+	return nil
+}
+
+/*
+ExpandUser returns a new Path with a leading "~" element replaced by the current
+user's home directory and a leading "~username" element replaced by the home directory
+of that user. Paths without such an element are returned unchanged.
+An error is returned if the home directory cannot be determined.
+
+This function utilizes os.UserHomeDir and user.Lookup.
+*/
+func (p *Path) ExpandUser() (*Path, error) {
+	if !strings.HasPrefix(p.path, "~") {
+		return p.Copy(), nil
+	}
+
+	first, rest, _ := strings.Cut(p.path, pathSeparator)
+
+	var home string
+	if first == "~" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		home = userHome
+	} else {
+		u, err := user.Lookup(first[1:])
+		if err != nil {
+			return nil, err
+		}
+		if u.HomeDir == "" {
+			return nil, fmt.Errorf("user %q has no home directory", u.Username)
+		}
+		home = u.HomeDir
+	}
+
+	return NewPath(home).JoinStrings(rest), nil
+}
+
+/*
+IsAbsolute returns whether this Path is absolute.
+
+On non-Windows operating systems, the Windows path root (e.g. 'C:\')
+is not considered a file root but as a regular (relative) path element.
+Thus, this function would return false.
+
+This function utilizes filepath.IsAbs.
+*/
+func (p *Path) IsAbsolute() bool {
+	return filepath.IsAbs(p.path)
+}
+
+/*
+IsRelative returns whether this Path is relative.
+
+This function returns the inverse of IsAbsolute.
+*/
+func (p *Path) IsRelative() bool {
+	return !p.IsAbsolute()
+}
+
+/*
+RelativeTo returns this Path relative to another.
+
+This function utilizes filepath.Rel.
+*/
+func (p *Path) RelativeTo(o *Path) (*Path, error) {
+	rp, err := filepath.Rel(o.path, p.path)
+	return NewPath(rp), err
+}
+
+/*
+RelOptions configures RelativeToWith.
+*/
+type RelOptions struct {
+
+	// WalkUp controls whether the result may contain ".." elements. If false,
+	// an error is returned unless this Path equals or is located below the other.
+	WalkUp bool
+}
+
+/*
+RelativeToWith returns this Path relative to another as in RelativeTo,
+but with configurable behaviour. By default, the result must not
+walk up the other Path using ".." elements.
+*/
+func (p *Path) RelativeToWith(o *Path, opts RelOptions) (*Path, error) {
+	rp, err := p.RelativeTo(o)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.WalkUp && !filepath.IsLocal(rp.path) {
+		return nil, fmt.Errorf("'%s' is not within '%s'", p.path, o.path)
+	}
+
+	return rp, nil
+}
+
+/*
+IsWithin returns whether this Path equals or is located below the base Path.
+The check is purely lexical, thus symbolic links are not considered, and
+absolute and relative Paths are never within each other.
+Use IsWithinFS to validate untrusted paths against existing directories.
+*/
+func (p *Path) IsWithin(base *Path) bool {
+	if p.IsAbsolute() != base.IsAbsolute() {
+		return false
+	}
+
+	rel, err := filepath.Rel(base.path, p.path)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+/*
+IsChildOf returns whether this Path is a direct child of the other Path.
+The check is lexical as in IsWithin.
+*/
+func (p *Path) IsChildOf(other *Path) bool {
+	if !p.IsDescendantOf(other) {
+		return false
+	}
+
+	// a direct child is exactly one local element below the other Path
+	rel, err := filepath.Rel(other.path, p.path)
+	return err == nil && filepath.IsLocal(rel) && filepath.Base(rel) == rel
+}
+
+/*
+IsParentOf returns whether this Path is the direct parent of the other Path.
+The check is lexical as in IsWithin.
+*/
+func (p *Path) IsParentOf(other *Path) bool {
+	return other.IsChildOf(p)
+}
+
+/*
+IsDescendantOf returns whether this Path is located anywhere below the other Path.
+Unlike IsWithin, a Path is not a descendant of itself.
+*/
+func (p *Path) IsDescendantOf(other *Path) bool {
+	return p.path != other.path && p.IsWithin(other)
+}
+
+/*
+IsAncestorOf returns whether the other Path is located anywhere below this Path.
+Unlike IsWithin, a Path is not an ancestor of itself.
+*/
+func (p *Path) IsAncestorOf(other *Path) bool {
+	return other.IsDescendantOf(p)
+}
+
+/*
+IsWithinFS returns whether this Path equals or is located below the base Path
+after resolving symbolic links. Both Paths are made absolute first and may not exist,
+in which case the symbolic links of their deepest existing parents are resolved.
+*/
+func (p *Path) IsWithinFS(base *Path) (bool, error) {
+	resolvedPath, err := resolveExisting(p.path)
+	if err != nil {
+		return false, err
+	}
+
+	resolvedBase, err := resolveExisting(base.path)
+	if err != nil {
+		return false, err
+	}
+
+	return NewPath(resolvedPath).IsWithin(NewPath(resolvedBase)), nil
+}
+
+/*
+CommonPath returns the deepest common ancestor of all passed Paths.
+Paths are compared lexically by their elements, e.g. the common path of
+"/a/bc" and "/a/b" is "/a". If relative Paths share no elements, "." is returned.
+
+An error is returned if no Paths are passed, or if absolute and relative Paths
+or Paths on different volumes are mixed.
+*/
+func CommonPath(paths ...*Path) (*Path, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no paths passed")
+	}
+
+	first := paths[0]
+	volume := filepath.VolumeName(first.path)
+	common := pathElements(first.path[len(volume):])
+
+	for _, other := range paths[1:] {
+		if other.IsAbsolute() != first.IsAbsolute() {
+			return nil, errors.New("cannot mix absolute and relative paths")
+		}
+
+		otherVolume := filepath.VolumeName(other.path)
+		if !strings.EqualFold(otherVolume, volume) {
+			return nil, errors.New("paths are located on different volumes")
+		}
+
+		elements := pathElements(other.path[len(otherVolume):])
+
+		shared := 0
+		for shared < len(common) && shared < len(elements) && common[shared] == elements[shared] {
+			shared++
+		}
+		common = common[:shared]
+	}
+
+	joined := filepath.Join(common...)
+	if first.IsAbsolute() {
+		joined = volume + pathSeparator + joined
+	} else if volume != "" {
+		joined = volume + joined
+	}
+
+	return NewPath(joined), nil
+}
+
+/*
+Absolute returns an absolute representation of this Path.
+If the Path is relative, it will be joined with the current working directory.
+
+This function utilizes filepath.Abs.
+*/
+func (p *Path) Absolute() (*Path, error) {
+	ap, err := filepath.Abs(p.path)
+	return NewPath(ap), err
+}
+
+/*
+AbsoluteTo returns an absolute representation of this Path towards another.
+If the Path is relative, it will be joined with the provided Path, else this Path is returned.
+
+Requires the other Path to be absolute.
+*/
+func (p *Path) AbsoluteTo(o *Path) (*Path, error) {
+
+	// if path is already absolute, ignore it
+	if p.IsAbsolute() {
+		return p, nil
+	}
+
+	if o.IsRelative() {
+		return nil, errors.New("other path must be absolute")
+	}
+
+	return o.Join(p), nil
+}
+
+/*
+Resolve resolves all symbolic links. If this Path is relative,
+the result will be relative to the current directory, unless
+one of the components is an absolute symbolic link.
+
+Resolve requires this Path to exist.
+
+This function utilizes filepath.EvalSymlinks.
+*/
+func (p *Path) Resolve() (*Path, error) {
+	if !p.Exists() {
+		return nil, errors.New("this path does not exist")
+	}
+
+	ep, err := filepath.EvalSymlinks(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPath(ep), nil
+}
+
+/*
+Join returns a new Path with all passed Path structs joined together.
+Use JoinStrings to join strings with this Path.
+
+This function utilizes filepath.Join.
+*/
+func (p *Path) Join(paths ...*Path) *Path {
+	pathsStr := make([]string, len(paths))
+	for i, path := range paths {
+		pathsStr[i] = path.path
+	}
+
+	return NewPath(filepath.Join(append([]string{p.path}, pathsStr...)...))
+}
+
+/*
+JoinStrings returns a new Path with all passed strings joined together.
+
+This function utilizes filepath.Join.
+*/
+func (p *Path) JoinStrings(paths ...string) *Path {
+	return NewPath(filepath.Join(append([]string{p.path}, paths...)...))
+}
+
+/*
+Iterdir returns the children of this directory, sorted by name.
+The special entries '.' and '..' are not included.
+
+This function utilizes os.ReadDir.
+*/
+func (p *Path) Iterdir() ([]*Path, error) {
+	entries, err := os.ReadDir(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]*Path, len(entries))
+	for idx, entry := range entries {
+		children[idx] = p.JoinStrings(entry.Name())
+	}
+
+	return children, nil
+}
+
+/*
+WalkOptions configures the traversal of Walk.
+*/
+type WalkOptions struct {
+
+	// SkipDir is called for every directory below the root. If it returns
+	// true, the directory and all its children are skipped.
+	SkipDir func(p *Path, d fs.DirEntry) bool
+
+	// CollectErrors controls whether errors abort the traversal. If true,
+	// the traversal continues and all errors are returned joined after the walk.
+	CollectErrors bool
+
+	// Ignore skips all entries below the root that are ignored by the matcher.
+	Ignore *IgnoreMatcher
+}
+
+/*
+Walk recursively traverses this Path in lexical order and calls fn for every
+entry, including this Path itself. Symbolic links are not followed.
+
+fn may return fs.SkipDir or fs.SkipAll to skip a directory or the remaining
+traversal. Any other error returned by fn, as well as errors while reading
+directories, is handled according to the passed WalkOptions.
+
+This function utilizes filepath.WalkDir.
+*/
+func (p *Path) Walk(fn func(p *Path, d fs.DirEntry) error, opts WalkOptions) error {
+	var errs []error
+
+	handleErr := func(err error) error {
+		if opts.CollectErrors {
+			errs = append(errs, err)
+			return nil
+		}
+
+		return err
+	}
+
+	err := filepath.WalkDir(p.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return handleErr(err)
+		}
+
+		current := NewPath(path)
+		if path != p.path && opts.Ignore != nil && opts.Ignore.Match(current, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() && path != p.path && opts.SkipDir != nil && opts.SkipDir(current, d) {
+			return fs.SkipDir
+		}
+
+		err = fn(current, d)
+		if err != nil && err != fs.SkipDir && err != fs.SkipAll {
+			return handleErr(err)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+WalkSeq returns an iterator that lazily traverses this Path in lexical order,
+including this Path itself. Symbolic links are not followed.
+
+Errors while reading entries are yielded together with the affected Path,
+the traversal continues afterward unless the loop is stopped.
+
+This function utilizes filepath.WalkDir.
+*/
+func (p *Path) WalkSeq() iter.Seq2[*Path, error] {
+	return func(yield func(*Path, error) bool) {
+		_ = filepath.WalkDir(p.path, func(path string, d fs.DirEntry, err error) error {
+			if !yield(NewPath(path), err) {
+				return fs.SkipAll
+			}
+
+			return nil
+		})
+	}
+}
+
+/*
+AsFS returns a file system rooted at this directory Path, which can be used
+with every API consuming fs.FS, such as fs.WalkDir or http.FS.
+Names passed to the file system use forward slashes as in fs.ValidPath.
+
+This function utilizes os.DirFS.
+*/
+func (p *Path) AsFS() fs.FS {
+	return os.DirFS(p.path)
+}
+
+/*
+FsPath is a path within an fs.FS instead of the operating system's file system.
+This allows addressing e.g. embed.FS, zip archives or fstest.MapFS using
+the same vocabulary as for Path. FsPath instances are immutable.
+
+Paths are slash-separated and relative to the file system's root as in fs.ValidPath.
+*/
+type FsPath struct {
+	fsys fs.FS
+	path string
+}
+
+/*
+PathOn returns a new FsPath for the passed path within fsys.
+The path is cleaned and leading slashes are removed, an empty path refers to the root.
+*/
+func PathOn(fsys fs.FS, name string) *FsPath {
+	cleaned := strings.TrimLeft(path.Clean("/"+name), "/")
+	if cleaned == "" {
+		cleaned = "."
+	}
+
+	return &FsPath{fsys: fsys, path: cleaned}
+}
+
+/*
+NewEmbeddedPath returns a new FsPath for the passed path within an embedded file system.
+*/
+func NewEmbeddedPath(fsys embed.FS, name string) *FsPath {
+	return PathOn(fsys, name)
+}
+
+/*
+FS returns the file system this FsPath is located in.
+*/
+func (p *FsPath) FS() fs.FS {
+	return p.fsys
+}
+
+/*
+String returns the slash-separated path within the file system.
+*/
+func (p *FsPath) String() string {
+	return p.path
+}
+
+/*
+Base returns the last element of this FsPath.
+*/
+func (p *FsPath) Base() string {
+	return path.Base(p.path)
+}
+
+/*
+Parent returns the parent directory of this FsPath.
+The parent of the root is the root itself.
+*/
+func (p *FsPath) Parent() *FsPath {
+	return &FsPath{fsys: p.fsys, path: path.Dir(p.path)}
+}
+
+/*
+JoinStrings returns a new FsPath with the passed elements appended.
+*/
+func (p *FsPath) JoinStrings(elements ...string) *FsPath {
+	return PathOn(p.fsys, path.Join(append([]string{p.path}, elements...)...))
+}
+
+/*
+Stat returns the file information of this FsPath.
+
+This function utilizes fs.Stat.
+*/
+func (p *FsPath) Stat() (fs.FileInfo, error) {
+	return fs.Stat(p.fsys, p.path)
+}
+
+/*
+Exists returns whether this FsPath exists.
+*/
+func (p *FsPath) Exists() bool {
+	_, err := p.Stat()
+	return err == nil
+}
+
+/*
+IsFile returns whether this FsPath exists and is a regular file.
+*/
+func (p *FsPath) IsFile() bool {
+	info, err := p.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
+
+/*
+IsDir returns whether this FsPath exists and is a directory.
+*/
+func (p *FsPath) IsDir() bool {
+	info, err := p.Stat()
+	return err == nil && info.IsDir()
+}
+
+/*
+Open opens this FsPath for reading.
+It is the caller's responsibility to close the file.
+*/
+func (p *FsPath) Open() (fs.File, error) {
+	return p.fsys.Open(p.path)
+}
+
+/*
+ReadBytes returns the content of this file.
+
+This function utilizes fs.ReadFile.
+*/
+func (p *FsPath) ReadBytes() ([]byte, error) {
+	return fs.ReadFile(p.fsys, p.path)
+}
+
+/*
+ReadText returns the content of this file as string.
+*/
+func (p *FsPath) ReadText() (string, error) {
+	content, err := p.ReadBytes()
+	return string(content), err
+}
+
+/*
+Iterdir returns all direct children of this directory in lexical order.
+
+This function utilizes fs.ReadDir.
+*/
+func (p *FsPath) Iterdir() ([]*FsPath, error) {
+	entries, err := fs.ReadDir(p.fsys, p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]*FsPath, len(entries))
+	for idx, entry := range entries {
+		children[idx] = p.JoinStrings(entry.Name())
+	}
+
+	return children, nil
+}
+
+/*
+MaterializeTo copies this file or directory tree out of its file system to dest.
+Missing directories are created and existing files are overwritten. As embedded
+files carry no meaningful permissions, files are created with 0666 and directories
+with 0777, both before umask.
+*/
+func (p *FsPath) MaterializeTo(dest *Path) error {
+	return fs.WalkDir(p.fsys, p.path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := dest
+		if name != p.path {
+			target = dest.JoinStrings(filepath.FromSlash(strings.TrimPrefix(name, p.path+"/")))
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(target.path, defaultDirPerm)
+		}
+
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("cannot materialize special file '%s'", name)
+		}
+
+		err = os.MkdirAll(filepath.Dir(target.path), defaultDirPerm)
+		if err != nil {
+			return err
+		}
+
+		content, err := fs.ReadFile(p.fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target.path, content, 0666)
+	})
+}
+
+/*
+Glob returns all paths below this directory matching the passed pattern in lexical order.
+The pattern is relative to this FsPath and supports '**' as in Path.Glob.
+*/
+func (p *FsPath) Glob(pattern string) ([]*FsPath, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("pattern must not be empty")
+	}
+
+	if !p.IsDir() {
+		return nil, errors.New("this path is not a directory")
+	}
+
+	compiled, err := compileExcludes([]string{pattern})
+	if err != nil {
+		return nil, err
+	}
+	segments := compiled[0]
+	recursive := hasRecursiveSegment(pattern)
+
+	var matches []*FsPath
+	err = fs.WalkDir(p.fsys, p.path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		var rel []string
+		if name != p.path {
+			rel = strings.Split(strings.TrimPrefix(name, p.path+"/"), "/")
+		}
+
+		if matchSegments(segments, rel, false) {
+			matches = append(matches, &FsPath{fsys: p.fsys, path: name})
+		}
+
+		// without '**', directories deeper than the pattern cannot contain matches
+		if d.IsDir() && !recursive && len(rel) >= len(segments) {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+
+	return matches, err
+}
+
+/*
+Glob returns all paths matching the given pattern within this Path's directory.
+
+A '**' pattern segment matches zero or more directories. Prefixing a pattern
+with a '**' segment thus matches it within this Path's whole directory tree.
+Symbolic links to directories are not followed while matching '**'.
+
+This function utilizes filepath.Glob. It ignores IO errors.
+*/
+func (p *Path) Glob(pattern string) ([]*Path, error) {
+	matches, err := nativeGlob(p, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*Path, len(matches))
+	for idx, match := range matches {
+		paths[idx] = NewPath(match)
+	}
+
+	return paths, nil
+}
+
+/*
+FullMatch returns whether this Path matches the passed pattern as a whole.
+In contrast to Glob, the matching is purely lexical and the pattern must cover
+the entire Path, e.g. "*.go" matches "main.go" but not "cmd/main.go".
+Absolute paths only match absolute patterns.
+
+A '**' pattern segment matches zero or more arbitrary path segments,
+every other segment is matched using filepath.Match.
+*/
+func (p *Path) FullMatch(pattern string) (bool, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return false, errors.New("pattern must not be empty")
+	}
+
+	patternSegments := splitPattern(pattern)
+	for _, segment := range patternSegments {
+		_, err := filepath.Match(segment, "")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	trimmedPattern := strings.TrimSpace(pattern)
+	patternIsAbs := filepath.IsAbs(trimmedPattern) || strings.HasPrefix(trimmedPattern, "/") ||
+		strings.HasPrefix(trimmedPattern, pathSeparator)
+	if patternIsAbs != p.IsAbsolute() {
+		return false, nil
+	}
+
+	return matchSegments(patternSegments, splitPattern(p.path), false), nil
+}
+
+/*
+GlobSeq returns an iterator that lazily yields all paths matching the given
+pattern within this Path's directory, as in Glob. Matches are yielded while
+the directory tree is traversed, without collecting them first.
+
+Invalid patterns and directories are reported by yielding a single error.
+IO errors during the traversal are ignored.
+
+Patterns with multiple '**' segments or '..' segments may reach the same path
+multiple times. To report it only once, all matches of such patterns are kept in memory.
+*/
+func (p *Path) GlobSeq(pattern string) iter.Seq2[*Path, error] {
+	return func(yield func(*Path, error) bool) {
+		err := checkGlob(p, pattern)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		walker := &globWalker{
+			fn: func(match string) bool {
+				return yield(NewPath(match), nil)
+			},
+		}
+
+		err = walker.glob(p.path, pattern)
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+/*
+GlobOptions configures the matching of GlobWith.
+*/
+type GlobOptions struct {
+
+	// Pattern is the pattern to match, as in Glob.
+	Pattern string
+
+	// Exclude contains patterns of paths to exclude. They are matched against
+	// the paths relative to the globbed directory, as in FullMatch.
+	// If a directory is excluded, all its children are excluded as well.
+	Exclude []string
+
+	// FollowSymlinks controls whether symbolic links to directories are
+	// followed while matching '**' segments.
+	FollowSymlinks bool
+
+	// CaseInsensitive controls whether the pattern and excludes are matched
+	// ignoring case, independent of the filesystem's case sensitivity.
+	CaseInsensitive bool
+}
+
+/*
+GlobWith returns all paths matching the given options within this Path's directory.
+For example, the excludes "vendor" and "testdata" remove all matches within
+these top-level directories, which are not traversed at all.
+
+It ignores IO errors.
+*/
+func (p *Path) GlobWith(opts GlobOptions) ([]*Path, error) {
+	err := checkGlob(p, opts.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []*Path
+	walker := &globWalker{
+		followSymlinks:  opts.FollowSymlinks,
+		caseInsensitive: opts.CaseInsensitive,
+		exclude: func(path string) bool {
+			return isExcluded(p.path, path, excludes, opts.CaseInsensitive)
+		},
+		fn: func(match string) bool {
+			paths = append(paths, NewPath(match))
+			return true
+		},
+	}
+
+	err = walker.glob(p.path, opts.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+/*
+Contains returns whether the passed pattern exist within this Path's directory.
+
+This function utilizes filepath.Glob.
+*/
+func (p *Path) Contains(pattern string) (bool, error) {
+	matches, err := nativeGlob(p, pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return len(matches) != 0, nil
+}
+
+/*
+BContains returns whether the passed pattern exists within this Path's directory.
+It wraps Contains and returns the boolean success value.
+*/
+func (p *Path) BContains(pattern string) bool {
+	contains, _ := p.Contains(pattern)
+	return contains
+}
+
+/*
+IsCaseSensitiveFs returns whether a given path is on a case-sensitive filesystem.
+
+Currently, this function checks the sensitivity using the path's base.
+*/
+func IsCaseSensitiveFs(p *Path) (bool, error) {
+	// IMPORTANT:
+	// It would make sense to check if this Path actually exists before
+	// continuing the check. But this does not make sense in the context
+	// of this function's goal.
+
+	// TODO Check sensitivity of parent parts --> underlying fs may have mounted
+	// 	multiple filesystems that are switching sensitivity.
+
+	alt := p.Parent()
+	alt = alt.JoinStrings(flipCase(p.Base()))
+
+	// get file stat for passed file (required for later comparison in os.SameFile)
+	pathInfo, err := os.Stat(p.path)
+	if err != nil {
+		return false, err
+	}
+
+	// if file does not exist, assume to be on case-sensitive filesystem
+	altInfo, err := os.Stat(alt.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// if both file exist, check if they are the same
+	// if they are equal, then the filesystem is case-insensitive,
+	// else the filesystem is case-sensitive
+	return !os.SameFile(pathInfo, altInfo), nil
+}
+
+/*
+EqualsFold returns whether two path strings are structurally the same under full
+Unicode case folding, e.g. "STRASSE" and "straße" are equal. Unlike lowercasing,
+case folding does not depend on locale-specific rules, thus the Turkish dotted
+"İ" does not match a plain "i". The strings are cleaned as by NewPath before they
+are compared.
+*/
+func EqualsFold(first string, second string) bool {
+	return foldCase(cleanPathString(first)) == foldCase(cleanPathString(second))
+}
+
+/*
+Equals returns whether this and another Path are structurally the same.
+It respects case sensitivity.
+*/
+func (p *Path) Equals(other *Path) bool {
+	return p.path == other.path
+}
+
+/*
+EqualsString returns whether the passed string matches this Path.
+
+This function converts the passed string to a Path object and calls Equals.
+*/
+func (p *Path) EqualsString(other string) bool {
+	return p.path == cleanPathString(other)
+}
+
+/*
+EqualsCi returns whether this and another Path are structurally the same.
+It ignores case sensitivity by comparing the full Unicode case foldings, see EqualsFold.
+*/
+func (p *Path) EqualsCi(other *Path) bool {
+	return EqualsFold(p.path, other.path)
+}
+
+/*
+EqualsStringCi returns whether the passed string matches this Path.
+It ignores case sensitivity by comparing the full Unicode case foldings, see EqualsFold.
+*/
+func (p *Path) EqualsStringCi(other string) bool {
+	return EqualsFold(p.path, other)
+}
+
+/*
+EqualsFS returns whether this and another Path are the same on the filesystem.
+The evaluation also considers filesystem case sensitivity.
+*/
+func (p *Path) EqualsFS(other *Path) bool {
+	structurallyIdentical := EqualsFold(p.path, other.path)
+	if !structurallyIdentical {
+		return false
+	}
+
+	// if equal in lowercase, proceed to check if path is on a
+	// case-sensitive filesystem or not
+	caseSensitive, err := IsCaseSensitiveFs(p)
+	if err != nil {
+		// return false in case of an error
+		return false
+	}
+
+	// if case-sensitive, compare both original strings
+	if caseSensitive {
+		return p.path == other.path
+	}
+
+	// if case-insensitive, return true
+	return true
+}
+
+/*
+ToPosix returns a string representation with forward slashes.
+*/
+func (p *Path) ToPosix() string {
+	return filepath.ToSlash(p.String())
+}
+
+/*
+ToWindows returns a string representation with backslashes, e.g. to generate
+configurations or scripts targeting Windows from any operating system.
+Whitespaces are not escaped.
+*/
+func (p *Path) ToWindows() string {
+	return strings.ReplaceAll(p.path, "/", `\`)
+}
+
+/*
+ToWindowsWithDrive returns a string representation with backslashes like ToWindows,
+prefixing rooted Paths without a drive or UNC share with the passed drive, e.g. "C:".
+*/
+func (p *Path) ToWindowsWithDrive(drive string) string {
+	windowsPath := p.ToWindows()
+	if strings.HasPrefix(windowsPath, `\`) && !strings.HasPrefix(windowsPath, `\\`) {
+		return drive + windowsPath
+	}
+
+	return windowsPath
+}
+
+/*
+AsURI returns this Path as a percent-encoded file URI, e.g. "file:///home/user/a%20b.txt",
+"file:///C:/dir" for Windows drive paths and "file://server/share/dir" for Windows UNC paths.
+Relative Paths cannot be expressed as file URIs, thus an error is returned for them.
+*/
+func (p *Path) AsURI() (string, error) {
+	if !p.IsAbsolute() {
+		return "", errors.New("relative path cannot be expressed as a file URI")
+	}
+
+	path := p.path
+	uri := &url.URL{Scheme: "file"}
+
+	// UNC paths only exist on Windows, a leading double slash is local elsewhere
+	server, share, rest, isUNC := "", "", "", false
+	if runtime.GOOS == "windows" {
+		// extended-length paths are expressed in their regular form
+		if rest, ok := strings.CutPrefix(path, `\\?\UNC\`); ok {
+			path = `\\` + rest
+		} else {
+			path = strings.TrimPrefix(path, `\\?\`)
+		}
+
+		server, share, rest, isUNC = splitUNC(path)
+	}
+
+	if isUNC {
+		uri.Host = server
+		uri.Path = "/" + share
+		if rest != "" {
+			uri.Path += "/" + filepath.ToSlash(rest)
+		}
+	} else {
+		uri.Path = filepath.ToSlash(path)
+		if !strings.HasPrefix(uri.Path, "/") {
+			uri.Path = "/" + uri.Path
+		}
+	}
+
+	return uri.String(), nil
+}
+
+/*
+AsURL returns this Path as the path component of a URL, using forward slashes
+as separators. Escaping is applied by the url.URL when it is formatted, e.g. "a b/c"
+becomes "a%20b/c". Use AsURI for absolute file URIs.
+*/
+func (p *Path) AsURL() *url.URL {
+	return &url.URL{Path: filepath.ToSlash(p.path)}
+}
+
+/*
+JoinURLPath maps the path component of the passed URL onto this Path, like a static
+file server serving this Path as its root. The URL path is cleaned as an absolute path,
+thus ".." elements can not escape this Path. An error is returned if the URL path
+contains elements that are invalid on this operating system, e.g. backslashes,
+null bytes or reserved names on Windows.
+
+This function utilizes filepath.Localize.
+*/
+func (p *Path) JoinURLPath(u *url.URL) (*Path, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if cleaned == "" {
+		return p.Copy(), nil
+	}
+
+	local, err := filepath.Localize(cleaned)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Path{path: filepath.Join(p.path, local)}, nil
+}
+
+/*
+QuoteShell returns this Path quoted for POSIX shells like sh and bash, so that it is
+passed as a single argument without any expansion. Paths consisting only of letters,
+digits and the characters "@%+=:,./-_" are returned unquoted, all other Paths are
+enclosed in single quotes.
+*/
+func (p *Path) QuoteShell() string {
+	isSafe := func(r rune) bool {
+		return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("@%+=:,./-_", r))
+	}
+
+	if strings.IndexFunc(p.path, func(r rune) bool { return !isSafe(r) }) == -1 {
+		return p.path
+	}
+
+	// single quotes can not be escaped within single quotes, thus they are
+	// closed, an escaped single quote is added and they are reopened
+	return "'" + strings.ReplaceAll(p.path, "'", `'\''`) + "'"
+}
+
+/*
+QuotePowerShell returns this Path enclosed in single quotes for PowerShell, so that
+it is passed as a single verbatim argument. Single quotes, including the typographic
+variants PowerShell treats as such, are escaped by doubling them.
+*/
+func (p *Path) QuotePowerShell() string {
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	for _, r := range p.path {
+		switch r {
+		case '\'', '\u2018', '\u2019', '\u201a', '\u201b':
+			builder.WriteRune(r)
+		}
+		builder.WriteRune(r)
+	}
+	builder.WriteByte('\'')
+
+	return builder.String()
+}
+
+/*
+WithName returns this Path but with another base.
+*/
+func (p *Path) WithName(name string) *Path {
+	return p.Parent().JoinStrings(name)
+}
+
+/*
+WithSuffix returns this Path with its last extension replaced by the passed suffix,
+or removed if the suffix is empty. A missing dot is prepended to the suffix.
+Paths without a name, e.g. the root, are returned unchanged.
+*/
+func (p *Path) WithSuffix(suffix string) *Path {
+	if !p.hasName() {
+		return p.Copy()
+	}
+
+	if suffix != "" && !strings.HasPrefix(suffix, ".") {
+		suffix = "." + suffix
+	}
+
+	return p.WithName(p.Stem() + suffix)
+}
+
+/*
+WithStem returns this Path with its name replaced by the passed stem,
+keeping all extensions, so that "output.tar.gz" becomes e.g. "release.tar.gz".
+Paths without a name, e.g. the root, are returned unchanged.
+*/
+func (p *Path) WithStem(stem string) *Path {
+	if !p.hasName() {
+		return p.Copy()
+	}
+
+	return p.WithName(stem + strings.Join(p.Extensions(), ""))
+}
+
+/*
+WithoutExtension returns this Path with its last extension removed.
+It is the Path counterpart of Stem.
+*/
+func (p *Path) WithoutExtension() *Path {
+	return p.WithSuffix("")
+}
+
+/*
+WithoutExtensions returns this Path with all extensions removed.
+It is the Path counterpart of MinimalStem.
+*/
+func (p *Path) WithoutExtensions() *Path {
+	if !p.hasName() {
+		return p.Copy()
+	}
+
+	return p.WithName(p.MinimalStem())
+}
+
+/*
+AddSuffix returns this Path with the passed suffix appended as an additional
+extension, so that "foo.tar" becomes e.g. "foo.tar.gz". A missing dot is prepended
+to the suffix. Paths without a name, e.g. the root, are returned unchanged.
+*/
+func (p *Path) AddSuffix(suffix string) *Path {
+	if !p.hasName() || suffix == "" {
+		return p.Copy()
+	}
+
+	if !strings.HasPrefix(suffix, ".") {
+		suffix = "." + suffix
+	}
+
+	return p.WithName(p.Base() + suffix)
+}
+
+/*
+WithSegments returns a new Path consisting of this Path's anchor, i.e. its volume name
+and root, followed by the passed segments. For relative Paths, this is equal to
+joining the segments.
+*/
+func (p *Path) WithSegments(segments ...string) *Path {
+	return NewPath(p.Anchor() + filepath.Join(segments...))
+}
+
+/*
+NormalizeNFC returns this Path in the Unicode normalization form C, i.e. with all
+characters canonically composed. Paths that only differ in their normalization,
+e.g. file names read from macOS, compare equal after normalizing both.
+*/
+func (p *Path) NormalizeNFC() *Path {
+	return &Path{path: normalizeNFC(p.path)}
+}
+
+/*
+NormalizeNFD returns this Path in the Unicode normalization form D, i.e. with all
+characters canonically decomposed, as stored by macOS.
+*/
+func (p *Path) NormalizeNFD() *Path {
+	return &Path{path: normalizeNFD(p.path)}
+}
+
+/*
+WithSlugifiedName returns this Path with its stem replaced by its slug as created
+by Slugify, keeping the lowercased extensions. Paths without a name, or whose
+slug would be empty, are returned unchanged.
+*/
+func (p *Path) WithSlugifiedName() *Path {
+	if !p.hasName() {
+		return p.Copy()
+	}
+
+	slug := Slugify(p.MinimalStem())
+	if slug == "" {
+		return p.Copy()
+	}
+
+	return p.WithName(slug + strings.ToLower(strings.Join(p.Extensions(), "")))
+}
+
+/*
+Slugify converts arbitrary text into a lowercase ASCII name suitable for files and
+directories, with words separated by single hyphens, e.g. "Über Café!" becomes "uber-cafe".
+Common Latin letters with diacritics are transliterated, other non-ASCII letters are dropped.
+*/
+func Slugify(s string) string {
+	var builder strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		replacement := ""
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			replacement = string(r)
+		case slugTransliterations[r] != "":
+			replacement = slugTransliterations[r]
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			// letters without transliteration are dropped without separating words
+			continue
+		default:
+			pendingHyphen = builder.Len() > 0
+			continue
+		}
+
+		if pendingHyphen {
+			builder.WriteByte('-')
+			pendingHyphen = false
+		}
+		builder.WriteString(replacement)
+	}
+
+	return builder.String()
+}
+
+/*
+NextAvailable returns the first Path not existing yet out of this Path and
+its siblings numbered like "report (1).txt", "report (2).txt" and so on.
+The number is inserted before the last extension.
+Note that another process may create the returned Path before it is used.
+*/
+func (p *Path) NextAvailable() (*Path, error) {
+	stem := p.Stem()
+	extension := p.Extension()
+
+	candidate := p.Copy()
+	for i := 1; ; i++ {
+		_, err := os.Lstat(candidate.path)
+		if os.IsNotExist(err) {
+			return candidate, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		candidate = p.WithName(fmt.Sprintf("%s (%d)%s", stem, i, extension))
+	}
+}
+
+/*
+RotateBackups rotates this file into numbered backups, so that e.g. "app.log"
+becomes "app.log.1", "app.log.1" becomes "app.log.2" and so on. Backups beyond
+keep are removed, even if the numbering has gaps, and if keep is 0, this file is removed.
+
+The suffixPattern is appended to the file name and must contain a single '%d' verb
+for the backup number, defaulting to ".%d". Nothing happens if this file does not exist.
+*/
+func (p *Path) RotateBackups(keep int, suffixPattern string) error {
+	if keep < 0 {
+		return errors.New("number of backups to keep must not be negative")
+	}
+
+	if suffixPattern == "" {
+		suffixPattern = ".%d"
+	}
+
+	if strings.Count(suffixPattern, "%") != 1 || strings.Count(suffixPattern, "%d") != 1 {
+		return errors.New("suffix pattern must contain a single '%d' verb")
+	}
+
+	if !p.LExists() {
+		return nil
+	}
+
+	backup := func(i int) string {
+		return p.path + fmt.Sprintf(suffixPattern, i)
+	}
+
+	// prune all backups that would exceed keep after rotating, including those
+	// above gaps in the numbering, e.g. after lowering keep
+	entries, err := os.ReadDir(p.Parent().path)
+	if err != nil {
+		return err
+	}
+
+	prefix, suffix, _ := strings.Cut(suffixPattern, "%d")
+	for _, entry := range entries {
+		digits, ok := strings.CutPrefix(entry.Name(), p.Base()+prefix)
+		if !ok {
+			continue
+		}
+
+		digits, ok = strings.CutSuffix(digits, suffix)
+		if !ok {
+			continue
+		}
+
+		// only names produced by the suffix pattern are backups
+		i, err := strconv.Atoi(digits)
+		if err != nil || i < max(keep, 1) || strconv.Itoa(i) != digits {
+			continue
+		}
+
+		err = os.Remove(backup(i))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if keep == 0 {
+		return os.Remove(p.path)
+	}
+
+	for i := keep - 1; i >= 1; i-- {
+		err := os.Rename(backup(i), backup(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(p.path, backup(1))
+}
+
+/*
+Mkdir creates this Path as a directory with the passed permissions.
+The parent directory must exist. An error is returned if this Path already exists.
+
+This function utilizes os.Mkdir.
+*/
+func (p *Path) Mkdir(perm os.FileMode) error {
+	return os.Mkdir(p.path, perm)
+}
+
+/*
+MkdirExistOk creates this Path as a directory with the passed permissions.
+In contrast to Mkdir, no error is returned if this Path is an existing directory.
+*/
+func (p *Path) MkdirExistOk(perm os.FileMode) error {
+	err := os.Mkdir(p.path, perm)
+	if err != nil && os.IsExist(err) && p.IsDir() {
+		return nil
+	}
+
+	return err
+}
+
+/*
+MkdirAll creates this Path as a directory along with all missing parents.
+No error is returned if this Path is an existing directory.
+
+This function utilizes os.MkdirAll.
+*/
+func (p *Path) MkdirAll(perm os.FileMode) error {
+	return os.MkdirAll(p.path, perm)
+}
+
+/*
+Unlink removes this Path if it's a file or a symbolic link.
+Symbolic links are removed themselves, not their targets.
+If missingOk is true, no error is returned if this Path does not exist.
+
+Use Rmdir to remove directories.
+*/
+func (p *Path) Unlink(missingOk bool) error {
+	info, err := os.Lstat(p.path)
+	if err != nil {
+		if missingOk && os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.IsDir() {
+		return errors.New("this path is a directory")
+	}
+
+	return os.Remove(p.path)
+}
+
+/*
+Rmdir removes this Path if it's an empty directory.
+
+Use Unlink to remove files.
+*/
+func (p *Path) Rmdir() error {
+	info, err := os.Lstat(p.path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return errors.New("this path is not a directory")
+	}
+
+	return os.Remove(p.path)
+}
+
+/*
+RemoveTree recursively removes this Path and all its children.
+
+As a safety measure, it refuses to remove the empty Path, the current working
+directory ('.'), the filesystem root and the user's home directory.
+If confirm is not nil, it is called with the absolute Path before removal and
+the removal is aborted with an error unless it returns true.
+
+No error is returned if this Path does not exist.
+
+This function utilizes os.RemoveAll.
+*/
+func (p *Path) RemoveTree(confirm func(p *Path) bool) error {
+	if p.path == "" || p.path == "." {
+		return errors.New("refusing to remove the empty path or current directory")
+	}
+
+	absPath, err := p.Absolute()
+	if err != nil {
+		return err
+	}
+
+	if absPath.Parent().Equals(absPath) {
+		return errors.New("refusing to remove the filesystem root")
+	}
+
+	home, err := NewHome()
+	if err == nil && absPath.Equals(home) {
+		return errors.New("refusing to remove the home directory")
+	}
+
+	if confirm != nil && !confirm(absPath) {
+		return errors.New("removal was not confirmed")
+	}
+
+	return os.RemoveAll(p.path)
+}
+
+/*
+Rename renames this Path to the target and returns the target Path.
+An error wrapping os.ErrExist is returned if the target already exists.
+Use Replace to overwrite existing targets.
+
+Checking the target's existence and renaming is not atomic.
+*/
+func (p *Path) Rename(target *Path) (*Path, error) {
+	_, err := os.Lstat(target.path)
+	if err == nil {
+		return nil, &os.LinkError{Op: "rename", Old: p.path, New: target.path, Err: os.ErrExist}
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return p.Replace(target)
+}
+
+/*
+Replace renames this Path to the target and returns the target Path.
+An existing target is replaced, as long as the operating system permits it.
+
+This function utilizes os.Rename.
+*/
+func (p *Path) Replace(target *Path) (*Path, error) {
+	err := os.Rename(p.path, target.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return target.Copy(), nil
+}
+
+/*
+Move moves this Path to dest. If renaming is not possible because source and
+destination are on different filesystems, this Path is recursively copied and
+removed afterward. File modes, modification times and symbolic links are preserved.
+
+An error wrapping os.ErrExist is returned if dest already exists.
+*/
+func (p *Path) Move(dest *Path) error {
+	_, err := p.Rename(dest)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+
+	err = copyTree(p.path, dest.path)
+	if err != nil {
+		// do not leave a partial copy behind
+		_ = os.RemoveAll(dest.path)
+		return err
+	}
+
+	return os.RemoveAll(p.path)
+}
+
+/*
+Open opens this Path using the passed flags and permissions.
+It is the caller's responsibility to close the file.
+
+This function utilizes os.OpenFile.
+*/
+func (p *Path) Open(flags int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(p.path, flags, perm)
+}
+
+/*
+OpenRead opens this Path for reading.
+It is the caller's responsibility to close the file.
+
+This function utilizes os.Open.
+*/
+func (p *Path) OpenRead() (*os.File, error) {
+	return os.Open(p.path)
+}
+
+/*
+BufferedReader is a buffered reader of an opened file, which has to be closed after use.
+*/
+type BufferedReader struct {
+	*bufio.Reader
+	file *os.File
+}
+
+/*
+Close closes the underlying file.
+*/
+func (r *BufferedReader) Close() error {
+	return r.file.Close()
+}
+
+/*
+BufferedWriter is a buffered writer of an opened file, which has to be closed after use.
+*/
+type BufferedWriter struct {
+	*bufio.Writer
+	file *os.File
+}
+
+/*
+Close flushes the buffered data and closes the underlying file.
+The file is closed even if flushing fails.
+*/
+func (w *BufferedWriter) Close() error {
+	err := w.Flush()
+	closeErr := w.file.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+/*
+OpenBufferedReader opens this Path for buffered reading.
+It is the caller's responsibility to close the reader.
+*/
+func (p *Path) OpenBufferedReader() (*BufferedReader, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BufferedReader{Reader: bufio.NewReader(file), file: file}, nil
+}
+
+/*
+OpenBufferedWriter creates or truncates this Path and opens it for buffered writing.
+It is the caller's responsibility to close the writer, which flushes the buffered data.
+
+This function utilizes os.Create.
+*/
+func (p *Path) OpenBufferedWriter() (*BufferedWriter, error) {
+	file, err := os.Create(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BufferedWriter{Writer: bufio.NewWriter(file), file: file}, nil
+}
+
+/*
+EnsureFile creates this Path as a file with the passed default content,
+but only if it does not exist yet. Missing parent directories are created.
+It returns whether the file was created.
+
+Existing files are never modified. An error is returned if this Path
+exists but is not a file. If writing the default content fails, the created
+file is removed again.
+*/
+func (p *Path) EnsureFile(defaultContent []byte, perm os.FileMode) (bool, error) {
+	if p.Exists() {
+		if !p.IsFile() {
+			return false, errors.New("this path exists but is not a file")
+		}
+
+		return false, nil
+	}
+
+	err := p.Parent().MkdirAll(defaultDirPerm)
+	if err != nil {
+		return false, err
+	}
+
+	// O_EXCL prevents overwriting a file that was created concurrently
+	file, err := os.OpenFile(p.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		if !os.IsExist(err) {
+			return false, err
+		}
+
+		// dangling symbolic links do not exist according to Exists, but are not created through
+		if !p.IsFile() {
+			return false, errors.New("this path exists but is not a file")
+		}
+
+		return false, nil
+	}
+
+	_, err = file.Write(defaultContent)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		// the file was created by this call, so a partially written one can be removed
+		_ = os.Remove(p.path)
+		return false, err
+	}
+
+	return true, nil
+}
+
+/*
+WriteIfChanged writes data to this Path, but only if the existing file content differs.
+Unchanged files are not touched, which preserves their modification time.
+It returns whether the file was written.
+
+If the file does not exist, it is created with the passed permissions.
+*/
+func (p *Path) WriteIfChanged(data []byte, perm os.FileMode) (bool, error) {
+	existing, err := os.ReadFile(p.path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+
+	err = os.WriteFile(p.path, data, perm)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/*
+ReadLines reads this file and returns its lines without line terminators.
+Both "\n" and "\r\n" terminate lines, and a trailing line terminator does not result in an empty line.
+*/
+func (p *Path) ReadLines() ([]string, error) {
+	var lines []string
+
+	for line, err := range p.Lines() {
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+/*
+Lines returns an iterator that lazily reads this file line by line, as in ReadLines.
+The file is only opened when iterating and closed when the iteration ends.
+Errors are yielded once, after which the iteration stops. Lines are not limited in length.
+*/
+func (p *Path) Lines() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		file, err := os.Open(p.path)
+		if err != nil {
+			yield("", err)
+			return
+		}
+		defer file.Close()
+
+		reader := bufio.NewReader(file)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				yield("", err)
+				return
+			}
+
+			if line != "" {
+				line = strings.TrimSuffix(line, "\n")
+				line = strings.TrimSuffix(line, "\r")
+
+				if !yield(line, nil) {
+					return
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+		}
+	}
+}
+
+/*
+LineOptions configures how WriteLines writes lines.
+*/
+type LineOptions struct {
+
+	// CRLF controls whether lines are terminated with "\r\n" instead of "\n".
+	CRLF bool
+
+	// OmitTrailingNewline controls whether the last line is written without a line terminator.
+	OmitTrailingNewline bool
+
+	// Perm contains the permissions of a newly created file. Defaults to 0666 before umask.
+	Perm os.FileMode
+}
+
+/*
+WriteLines writes the passed lines to this file, which is created or truncated,
+terminating them as configured by opts.
+*/
+func (p *Path) WriteLines(lines []string, opts LineOptions) error {
+	terminator := "\n"
+	if opts.CRLF {
+		terminator = "\r\n"
+	}
+
+	perm := opts.Perm
+	if perm == 0 {
+		perm = 0666
+	}
+
+	file, err := os.OpenFile(p.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for i, line := range lines {
+		_, err = writer.WriteString(line)
+		if err == nil && (i < len(lines)-1 || !opts.OmitTrailingNewline) {
+			_, err = writer.WriteString(terminator)
+		}
+
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+/*
+MkdirTemp creates a new temporary directory within this Path's directory
+and returns its Path. This is useful for temporary entries that have to live on
+the same filesystem as their final destination, e.g. for atomic renames.
+
+The pattern is handled as in os.MkdirTemp.
+
+This function utilizes os.MkdirTemp.
+*/
+func (p *Path) MkdirTemp(pattern string) (*Path, error) {
+	dir, err := os.MkdirTemp(p.path, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPath(dir), nil
+}
+
+/*
+TempChild returns a new Path inside this Path's directory with a random name that
+does not exist yet, without creating anything. The pattern is handled as in os.CreateTemp,
+i.e. the random string replaces the last "*" or is appended otherwise.
+Note that the entry may be created by another process before it is used;
+prefer MkdirTemp and CreateTemp where possible.
+*/
+func (p *Path) TempChild(pattern string) (*Path, error) {
+	if strings.ContainsRune(pattern, filepath.Separator) || strings.ContainsRune(pattern, '/') {
+		return nil, errors.New("pattern contains path separator")
+	}
+
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+
+	for range 10000 {
+		var random [8]byte
+		_, err := rand.Read(random[:])
+		if err != nil {
+			return nil, err
+		}
+
+		child := filepath.Join(p.path, prefix+hex.EncodeToString(random[:])+suffix)
+		_, err = os.Lstat(child)
+		if errors.Is(err, fs.ErrNotExist) {
+			return NewPath(child), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("no unused name found for pattern " + pattern)
+}
+
+/*
+CreateTemp creates a new temporary file within this Path's directory,
+opens it for reading and writing and returns its Path and the opened file.
+It is the caller's responsibility to close the file.
+
+The pattern is handled as in os.CreateTemp.
+
+This function utilizes os.CreateTemp.
+*/
+func (p *Path) CreateTemp(pattern string) (*Path, *os.File, error) {
+	file, err := os.CreateTemp(p.path, pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewPath(file.Name()), file, nil
+}
+
+/*
+Checksum returns the hex-encoded hash of this file's content.
+The passed function creates the hash, e.g. sha256.New.
+The file is streamed and not loaded into memory at once.
+*/
+func (p *Path) Checksum(h func() hash.Hash) (string, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := h()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+/*
+SHA256 returns the hex-encoded SHA-256 hash of this file's content.
+*/
+func (p *Path) SHA256() (string, error) {
+	return p.Checksum(sha256.New)
+}
+
+/*
+MD5 returns the hex-encoded MD5 hash of this file's content.
+MD5 is not collision resistant and should only be used for compatibility.
+*/
+func (p *Path) MD5() (string, error) {
+	return p.Checksum(md5.New)
+}
+
+/*
+TreeChecksumOptions configures the calculation of TreeChecksum.
+*/
+type TreeChecksumOptions struct {
+
+	// Hash creates the hash used for files and the tree. Defaults to sha256.New.
+	Hash func() hash.Hash
+
+	// IncludeModes controls whether the permission bits of entries are part of the checksum.
+	IncludeModes bool
+
+	// Exclude contains patterns of paths to exclude, as in GlobOptions.
+	Exclude []string
+}
+
+/*
+TreeChecksum returns a hex-encoded checksum of this Path's directory tree.
+The checksum covers the sorted relative paths and types of all entries,
+the contents of files and the targets of symbolic links. Thus, two trees
+with equal checksums have the same structure and contents.
+
+Symbolic links are not followed. Modification times are never included.
+*/
+func (p *Path) TreeChecksum(opts TreeChecksumOptions) (string, error) {
+	newHash := opts.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+
+	excludes, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return "", err
+	}
+
+	treeHash := newHash()
+
+	err = filepath.WalkDir(p.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == p.path && d.IsDir() {
+			return nil
+		}
+
+		if isExcluded(p.path, path, excludes, false) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(p.path, path)
+		if err != nil {
+			return err
+		}
+
+		var entryType, content string
+		switch {
+		case d.IsDir():
+			entryType = "dir"
+		case d.Type()&fs.ModeSymlink != 0:
+			entryType = "symlink"
+			content, err = os.Readlink(path)
+		case d.Type().IsRegular():
+			entryType = "file"
+			content, err = NewPath(path).Checksum(newHash)
+		default:
+			return fmt.Errorf("cannot checksum special file '%s'", path)
+		}
+		if err != nil {
+			return err
+		}
+
+		mode := ""
+		if opts.IncludeModes {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			mode = info.Mode().Perm().String()
+		}
+
+		// entries are separated by null bytes, which cannot be part of paths
+		_, err = fmt.Fprintf(treeHash, "%s\x00%s\x00%s\x00%s\x00", entryType, filepath.ToSlash(rel), mode, content)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(treeHash.Sum(nil)), nil
+}
+
+/*
+Copy creates a copy of this Path.
+
+Fresh out of the oven, just for you.
+*/
+func (p *Path) Copy() *Path {
+	return NewPath(p.path)
+}
+
+/*
+Value returns this Path as a comparable PathValue.
+*/
+func (p *Path) Value() PathValue {
+	return PathValue{path: p.path}
+}
+
+/*
+PathValue is a comparable value representation of a Path, e.g. for map keys and
+struct fields. Unlike *Path, whose equality compares pointers, two PathValues are
+equal if their cleaned path strings are equal. All methods use value receivers,
+thus PathValues can be encoded and decoded as text wherever they are stored.
+
+Create a new instance using NewPathValue() or Path.Value().
+*/
+type PathValue struct {
+	path string
+}
+
+/*
+NewPathValue returns a new PathValue of the passed path string, which is cleaned as by NewPath.
+*/
+func NewPathValue(path string) PathValue {
+	return NewPath(path).Value()
+}
+
+/*
+Path returns a new Path of this PathValue.
+*/
+func (v PathValue) Path() *Path {
+	return &Path{path: v.path}
+}
+
+/*
+String returns this PathValue as a string, equal to Path.String.
+*/
+func (v PathValue) String() string {
+	return v.Path().String()
+}
+
+/*
+MarshalText marshals this PathValue into a byte array, equal to Path.MarshalText.
+Implements the encoding.TextMarshaler interface, which also allows PathValues as JSON object keys.
+*/
+func (v PathValue) MarshalText() ([]byte, error) {
+	return []byte(v.path), nil
+}
+
+/*
+UnmarshalText unmarshals any byte array into a PathValue, equal to Path.UnmarshalText.
+Implements the encoding.TextUnmarshaler interface.
+*/
+func (v *PathValue) UnmarshalText(text []byte) error {
+	*v = NewPathValue(string(text))
+	return nil
+}
+
+// whitespaceEscaping controls whether String escapes whitespaces, see SetWhitespaceEscaping.
+var whitespaceEscaping atomic.Bool
+
+/*
+SetWhitespaceEscaping controls whether String escapes whitespaces with a backslash,
+e.g. "with\\ whitespace", which is useful when displaying Paths that are pasted into shells.
+Escaping is disabled by default, applies to all Paths and never applies on Windows.
+Use Raw to always obtain the unescaped path string, e.g. for passing it to os functions.
+*/
+func SetWhitespaceEscaping(enabled bool) {
+	whitespaceEscaping.Store(enabled)
+}
+
+/*
+String returns this Path as a string.
+Whitespaces are escaped if enabled using SetWhitespaceEscaping.
+*/
+func (p *Path) String() string {
+	pathStr := p.path
+
+	// re-add removed whitespace escape characters
+	if whitespaceEscaping.Load() && runtime.GOOS != "windows" {
+		pathStr = strings.ReplaceAll(pathStr, " ", "\\ ")
+	}
+
+	return pathStr
+}
+
+/*
+Raw returns the cleaned path string of this Path without any escaping.
+*/
+func (p *Path) Raw() string {
+	return p.path
+}
+
+/*
+Format formats this Path for the fmt package, supporting the following verbs:
+
+	%s, %v  the path as returned by String
+	%q      the quoted path without any escaping
+	%+v     the absolute path, or the path itself if it can not be made absolute
+	%#v     a Go expression creating this Path, e.g. pathlib.NewPath("foo/bar")
+
+Other verbs like %x are applied to the path as returned by String.
+Width, precision and flags are applied like for strings.
+
+The expression printed by %#v always uses NewPath, thus Paths created with
+NewPathWithOptions may be cleaned differently when it is evaluated.
+Implements the fmt.Formatter interface.
+*/
+func (p *Path) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, "pathlib.NewPath(%q)", p.path)
+	case verb == 'v' && f.Flag('+'):
+		absolute, err := p.Absolute()
+		if err != nil {
+			absolute = p
+		}
+		fmt.Fprintf(f, formatWithVerb(f, 's'), absolute.String())
+	case verb == 'v' || verb == 's':
+		fmt.Fprintf(f, formatWithVerb(f, 's'), p.String())
+	case verb == 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), p.path)
+	default:
+		// other verbs are applied to the string as when formatting a fmt.Stringer
+		fmt.Fprintf(f, fmt.FormatString(f, verb), p.String())
+	}
+}
+
+/*
+UnmarshalText unmarshalls any byte array into a Path type.
+Implements the encoding.TextUnmarshaler interface.
+
+TOML libraries like github.com/BurntSushi/toml and github.com/pelletier/go-toml/v2
+decode string values into Path fields using this interface, thus cleaning is applied
+to paths read from TOML configuration files without further integration.
+*/
+func (p *Path) UnmarshalText(text []byte) error {
+	*p = *NewPath(string(text))
+	return nil
+}
+
+/*
+MarshalText marshals this Path into a byte array.
+Implements the encoding.TextMarshaler interface, which is also used
+by TOML libraries to encode Paths as strings.
+*/
+func (p *Path) MarshalText() (text []byte, err error) {
+	return []byte(p.path), nil
+}
+
+/*
+UnmarshalBinary unmarshals binary data into a Path type, equal to UnmarshalText.
+Implements the encoding.BinaryUnmarshaler interface.
+*/
+func (p *Path) UnmarshalBinary(data []byte) error {
+	return p.UnmarshalText(data)
+}
+
+/*
+MarshalBinary marshals this Path into binary data, equal to MarshalText.
+Implements the encoding.BinaryMarshaler interface.
+*/
+func (p *Path) MarshalBinary() (data []byte, err error) {
+	return p.MarshalText()
+}
+
+/*
+GobEncode encodes this Path for transmission by the encoding/gob package,
+which does not encode unexported fields. The encoding equals MarshalText.
+Implements the gob.GobEncoder interface.
+
+It uses a value receiver, as gob can not call pointer methods on unaddressable
+values, e.g. Path fields of structs that are encoded by value.
+*/
+func (p Path) GobEncode() ([]byte, error) {
+	return p.MarshalText()
+}
+
+/*
+GobDecode decodes a Path encoded by GobEncode. The decoded path is cleaned as in UnmarshalText.
+Implements the gob.GobDecoder interface.
+*/
+func (p *Path) GobDecode(data []byte) error {
+	return p.UnmarshalText(data)
+}
+
+/*
+MarshalXML encodes this Path as the character data of an XML element.
+A nil Path is omitted. Implements the xml.Marshaler interface.
+
+As for MarshalText, structs containing Path fields have to be encoded through a pointer.
+*/
+func (p *Path) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if p == nil {
+		return nil
+	}
+
+	return e.EncodeElement(p.path, start)
+}
+
+/*
+UnmarshalXML decodes the character data of an XML element into this Path.
+The decoded path is cleaned as in UnmarshalText.
+Implements the xml.Unmarshaler interface.
+*/
+func (p *Path) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	err := d.DecodeElement(&text, &start)
+	if err != nil {
+		return err
+	}
+
+	return p.UnmarshalText([]byte(text))
+}
+
+/*
+MarshalXMLAttr encodes this Path as an XML attribute value.
+A nil Path is omitted. Implements the xml.MarshalerAttr interface.
+
+As for MarshalText, structs containing Path fields have to be encoded through a pointer.
+*/
+func (p *Path) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if p == nil {
+		return xml.Attr{}, nil
+	}
+
+	return xml.Attr{Name: name, Value: p.path}, nil
+}
+
+/*
+UnmarshalXMLAttr decodes an XML attribute value into this Path.
+The decoded path is cleaned as in UnmarshalText.
+Implements the xml.UnmarshalerAttr interface.
+*/
+func (p *Path) UnmarshalXMLAttr(attr xml.Attr) error {
+	return p.UnmarshalText([]byte(attr.Value))
+}
+
+/*
+Set parses and cleans the passed string into this Path.
+An error is returned for empty or invalid strings, see NewPathE.
+Together with String, it implements the flag.Value interface.
+*/
+func (p *Path) Set(value string) error {
+	path, err := NewPathE(value)
+	if err != nil {
+		return err
+	}
+
+	*p = *path
+	return nil
+}
+
+/*
+PathVar defines a Path flag with the passed name, default value and usage string
+in the passed flag set, storing the flag's value in p. The default value is cleaned
+as by NewPath, thus an empty default value results in the same Path as NewPath("").
+If fs is nil, flag.CommandLine is used.
+*/
+func PathVar(fs *flag.FlagSet, p *Path, name string, value string, usage string) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	*p = *NewPath(value)
+
+	fs.Var(p, name, usage)
+}
+
+/*
+PathFlag defines a Path flag with the passed name, default value and usage string
+in the passed flag set and returns the Path storing the flag's value.
+See PathVar for details.
+*/
+func PathFlag(fs *flag.FlagSet, name string, value string, usage string) *Path {
+	p := new(Path)
+	PathVar(fs, p, name, value, usage)
+	return p
+}
+
+/*
+Comparison is the result of comparing two Paths using Compare.
+Each field reports whether the respective property differs between both Paths.
+*/
+type Comparison struct {
+	// Size reports whether the sizes differ.
+	Size bool
+
+	// Mode reports whether the file modes (including permissions) differ.
+	Mode bool
+
+	// ModTime reports whether the modification times differ.
+	ModTime bool
+
+	// Owner reports whether the owning user or group differ.
+	// It is always false on platforms that do not expose ownership.
+	Owner bool
+
+	// LinkTarget reports whether the targets of symbolic links differ.
+	LinkTarget bool
+
+	// Content reports whether the file contents differ.
+	// It is only evaluated if content comparison was requested.
+	Content bool
+}
+
+/*
+Differs returns whether any of the compared properties differ.
+*/
+func (c *Comparison) Differs() bool {
+	return c.Size || c.Mode || c.ModTime || c.Owner || c.LinkTarget || c.Content
+}
+
+/*
+Compare compares the metadata of two Paths and returns a report of all differences.
+Symbolic links are not followed, instead their targets are compared.
+If content is true, the contents of regular files are compared as well.
+
+Both Paths are required to exist.
+*/
+func Compare(a *Path, b *Path, content bool) (*Comparison, error) {
+	aInfo, err := os.Lstat(a.path)
+	if err != nil {
+		return nil, err
+	}
+
+	bInfo, err := os.Lstat(b.path)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{
+		Size:    aInfo.Size() != bInfo.Size(),
+		Mode:    aInfo.Mode() != bInfo.Mode(),
+		ModTime: !aInfo.ModTime().Equal(bInfo.ModTime()),
+	}
+
+	aUid, aGid, aOk := fileOwner(aInfo)
+	bUid, bGid, bOk := fileOwner(bInfo)
+	if aOk && bOk {
+		comparison.Owner = aUid != bUid || aGid != bGid
+	}
+
+	aIsLink := aInfo.Mode()&os.ModeSymlink != 0
+	bIsLink := bInfo.Mode()&os.ModeSymlink != 0
+	if aIsLink && bIsLink {
+		aTarget, err := os.Readlink(a.path)
+		if err != nil {
+			return nil, err
+		}
+
+		bTarget, err := os.Readlink(b.path)
+		if err != nil {
+			return nil, err
+		}
+
+		comparison.LinkTarget = aTarget != bTarget
+	} else {
+		comparison.LinkTarget = aIsLink != bIsLink
+	}
+
+	if content && aInfo.Mode().IsRegular() && bInfo.Mode().IsRegular() {
+		// files with different sizes cannot have the same content
+		if comparison.Size {
+			comparison.Content = true
+		} else {
+			equal, err := equalFileContents(a.path, b.path)
+			if err != nil {
+				return nil, err
+			}
+
+			comparison.Content = !equal
+		}
+	}
+
+	return comparison, nil
+}
+
+/*
+CompareTreesOptions configures the comparison of CompareTrees.
+*/
+type CompareTreesOptions struct {
+
+	// ModTime controls whether files with different modification times are reported as differing.
+	// The modification times of symbolic links are not compared.
+	ModTime bool
+
+	// Mode controls whether files and directories with different permissions are reported as differing.
+	Mode bool
+
+	// Content controls whether the contents of files with equal sizes are compared.
+	Content bool
+
+	// Exclude contains patterns of paths to exclude, as in GlobOptions.
+	Exclude []string
+}
+
+/*
+TreeDiff is the result of comparing two directory trees using CompareTrees.
+All Paths are relative to the compared trees' roots and sorted.
+*/
+type TreeDiff struct {
+
+	// OnlyLeft contains the entries that only exist in the left tree.
+	OnlyLeft []*Path
+
+	// OnlyRight contains the entries that only exist in the right tree.
+	OnlyRight []*Path
+
+	// Differing contains the entries that exist in both trees, but differ in type,
+	// size, symbolic link target, and, if requested, modification time, permissions or content.
+	Differing []*Path
+}
+
+/*
+Equal returns whether no differences were found.
+*/
+func (d *TreeDiff) Equal() bool {
+	return len(d.OnlyLeft) == 0 && len(d.OnlyRight) == 0 && len(d.Differing) == 0
+}
+
+/*
+CompareTrees compares this Path's directory tree (left) with another (right).
+Symbolic links are not followed. Directories only existing in one tree are
+reported together with all their children.
+*/
+func (p *Path) CompareTrees(other *Path, opts CompareTreesOptions) (TreeDiff, error) {
+	excludes, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return TreeDiff{}, err
+	}
+
+	left, err := treeEntries(p.path, excludes)
+	if err != nil {
+		return TreeDiff{}, err
+	}
+
+	right, err := treeEntries(other.path, excludes)
+	if err != nil {
+		return TreeDiff{}, err
+	}
+
+	diff := TreeDiff{}
+
+	for _, rel := range sortedKeys(left) {
+		leftMode := left[rel]
+		rightMode, ok := right[rel]
+		if !ok {
+			diff.OnlyLeft = append(diff.OnlyLeft, NewPath(rel))
+			continue
+		}
+
+		if leftMode.Type() != rightMode.Type() {
+			diff.Differing = append(diff.Differing, NewPath(rel))
+			continue
+		}
+
+		// the sizes and modification times of directories depend on their children,
+		// so only their permissions are compared
+		if leftMode.IsDir() && !opts.Mode {
+			continue
+		}
+
+		comparison, err := Compare(p.JoinStrings(rel), other.JoinStrings(rel), opts.Content)
+		if err != nil {
+			return TreeDiff{}, err
+		}
+
+		if leftMode.IsDir() {
+			if comparison.Mode {
+				diff.Differing = append(diff.Differing, NewPath(rel))
+			}
+			continue
+		}
+
+		// the modification times of symbolic links generally cannot be set and are ignored
+		isLink := leftMode&fs.ModeSymlink != 0
+
+		if comparison.Size || comparison.LinkTarget || comparison.Content ||
+			(opts.ModTime && comparison.ModTime && !isLink) || (opts.Mode && comparison.Mode) {
+			diff.Differing = append(diff.Differing, NewPath(rel))
+		}
+	}
+
+	for _, rel := range sortedKeys(right) {
+		if _, ok := left[rel]; !ok {
+			diff.OnlyRight = append(diff.OnlyRight, NewPath(rel))
+		}
+	}
+
+	return diff, nil
+}
+
+/*
+SyncOptions configures the synchronization of SyncTo.
+*/
+type SyncOptions struct {
+
+	// Delete controls whether entries in the destination that do not exist in the source are removed.
+	Delete bool
+
+	// Checksum controls whether files are compared by content instead of modification time.
+	Checksum bool
+
+	// DryRun controls whether the changes are only reported without being applied.
+	DryRun bool
+
+	// Exclude contains patterns of paths to exclude, as in GlobOptions.
+	// Excluded entries are neither copied nor deleted.
+	Exclude []string
+}
+
+/*
+SyncReport lists the changes made by SyncTo.
+All Paths are relative to the synchronized trees' roots and sorted.
+*/
+type SyncReport struct {
+
+	// Copied contains the entries that were newly created in the destination.
+	Copied []*Path
+
+	// Updated contains the entries that were replaced in the destination.
+	Updated []*Path
+
+	// Deleted contains the entries that were removed from the destination.
+	Deleted []*Path
+}
+
+/*
+SyncTo makes the dest directory tree match this Path's directory tree.
+New entries are copied, and entries differing in type, size, symbolic link target,
+permissions and modification time (or content, if opts.Checksum is set) are replaced.
+Directories differing in permissions are updated in place.
+Modes and modification times are preserved as by Move. If dest does not exist, it is created.
+
+Replaced files are removed before being copied, so they are briefly missing in dest.
+*/
+func (p *Path) SyncTo(dest *Path, opts SyncOptions) (SyncReport, error) {
+	sourceInfo, err := os.Stat(p.path)
+	if err != nil {
+		return SyncReport{}, err
+	}
+
+	if !sourceInfo.IsDir() {
+		return SyncReport{}, errors.New("this path is not a directory")
+	}
+
+	if !dest.Exists() {
+		if opts.DryRun {
+			excludes, err := compileExcludes(opts.Exclude)
+			if err != nil {
+				return SyncReport{}, err
+			}
+
+			entries, err := treeEntries(p.path, excludes)
+			if err != nil {
+				return SyncReport{}, err
+			}
+
+			report := SyncReport{}
+			for _, rel := range sortedKeys(entries) {
+				report.Copied = append(report.Copied, NewPath(rel))
+			}
+
+			return report, nil
+		}
+
+		err = os.MkdirAll(dest.path, defaultDirPerm)
+		if err != nil {
+			return SyncReport{}, err
+		}
+	}
+
+	diff, err := p.CompareTrees(dest, CompareTreesOptions{
+		ModTime: !opts.Checksum,
+		Mode:    true,
+		Content: opts.Checksum,
+		Exclude: opts.Exclude,
+	})
+	if err != nil {
+		return SyncReport{}, err
+	}
+
+	report := SyncReport{Copied: diff.OnlyLeft, Updated: diff.Differing}
+	if opts.Delete {
+		report.Deleted = diff.OnlyRight
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// deletions happen first, so that replaced directories do not collide with them
+	var deleted []string
+	for _, rel := range report.Deleted {
+		// skip entries already removed together with their parent directory
+		if len(deleted) > 0 && strings.HasPrefix(rel.path, deleted[len(deleted)-1]+string(filepath.Separator)) {
+			continue
+		}
+
+		err = os.RemoveAll(dest.JoinStrings(rel.path).path)
+		if err != nil {
+			return report, err
+		}
+		deleted = append(deleted, rel.path)
+	}
+
+	type copiedDir struct {
+		path string
+		info fs.FileInfo
+	}
+
+	var dirs []copiedDir
+
+	// sorted paths guarantee that parents are handled before their children
+	for _, rel := range append(append([]*Path{}, report.Updated...), report.Copied...) {
+		source := p.JoinStrings(rel.path).path
+		target := dest.JoinStrings(rel.path).path
+
+		info, err := os.Lstat(source)
+		if err != nil {
+			return report, err
+		}
+
+		// existing directories keep their children and only receive the source's mode
+		if info.IsDir() {
+			targetInfo, err := os.Lstat(target)
+			if err == nil && targetInfo.IsDir() {
+				dirs = append(dirs, copiedDir{path: target, info: info})
+				continue
+			}
+		}
+
+		err = os.RemoveAll(target)
+		if err != nil {
+			return report, err
+		}
+
+		err = copyEntry(source, target, info)
+		if err != nil {
+			return report, err
+		}
+
+		if info.IsDir() {
+			dirs = append(dirs, copiedDir{path: target, info: info})
+		}
+	}
+
+	// apply in reverse order so that children are handled before their parents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		err = applyDirInfo(dirs[i].path, dirs[i].info)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+/*
+ArchiveOptions configures the archive creation of ZipTo.
+*/
+type ArchiveOptions struct {
+
+	// Include contains patterns of paths to include, as in GlobOptions.
+	// Entries match if they or one of their parent directories match.
+	// If empty, all entries are included.
+	Include []string
+
+	// Exclude contains patterns of paths to exclude, as in GlobOptions.
+	// Exclusions take precedence over inclusions.
+	Exclude []string
+}
+
+/*
+ZipTo writes this Path into a zip archive at dest.
+If this Path is a file, the archive contains a single entry with its name.
+If it is a directory, its entries are stored relative to it in lexical order,
+so that equal trees result in equally ordered archives.
+Symbolic links are stored as links and not followed.
+On failure, the partially written archive is removed.
+*/
+func (p *Path) ZipTo(dest *Path, opts ArchiveOptions) error {
+	entries, err := archiveEntries(p.path, dest.path, opts)
+	if err != nil {
+		return err
+	}
+
+	return writeArchive(dest.path, func(w io.Writer) error {
+		return writeZip(w, entries)
+	})
+}
+
+/*
+Compression is the compression of archives written by TarTo.
+*/
+type Compression int
+
+const (
+	// CompressionNone writes an uncompressed tar archive.
+	CompressionNone Compression = iota
+
+	// CompressionGzip writes a gzip-compressed tar archive.
+	CompressionGzip
+)
+
+/*
+TarTo writes this Path into a tar archive at dest, optionally compressed.
+Entries are stored as in ZipTo, including their permissions, modification
+times and ownership. Symbolic links are stored as links and not followed.
+On failure, the partially written archive is removed.
+*/
+func (p *Path) TarTo(dest *Path, compression Compression) error {
+	if compression != CompressionNone && compression != CompressionGzip {
+		return fmt.Errorf("unknown compression %d", compression)
+	}
+
+	entries, err := archiveEntries(p.path, dest.path, ArchiveOptions{})
+	if err != nil {
+		return err
+	}
+
+	return writeArchive(dest.path, func(w io.Writer) error {
+		if compression == CompressionNone {
+			return writeTar(w, entries)
+		}
+
+		gzipWriter := gzip.NewWriter(w)
+		err := writeTar(gzipWriter, entries)
+		if err != nil {
+			return err
+		}
+
+		return gzipWriter.Close()
+	})
+}
+
+/*
+ExtractTo extracts this zip, tar or gzip-compressed tar archive into destDir,
+which is created if it does not exist. The format is detected from the archive's content.
+
+Entries whose names or symbolic link targets would escape destDir are rejected
+with an error, as are hard links and special files. Symbolic links are created after
+all other entries and are resolved against the extracted tree, so links pointing
+through other links can not escape destDir either. Existing files are not overwritten.
+Permissions and modification times are restored.
+*/
+func (p *Path) ExtractTo(destDir *Path) error {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// the tar magic is located at offset 257
+	magic := make([]byte, 262)
+	n, err := io.ReadFull(file, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	magic = magic[:n]
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	isZip := bytes.HasPrefix(magic, []byte("PK\x03\x04")) || bytes.HasPrefix(magic, []byte("PK\x05\x06"))
+	isGzip := bytes.HasPrefix(magic, []byte{0x1f, 0x8b})
+	isTar := bytes.HasPrefix(magic[min(len(magic), 257):], []byte("ustar"))
+
+	if !isZip && !isGzip && !isTar {
+		return errors.New("unknown archive format")
+	}
+
+	err = os.MkdirAll(destDir.path, defaultDirPerm)
+	if err != nil {
+		return err
+	}
+
+	extractor := &archiveExtractor{dest: destDir.path}
+
+	switch {
+	case isZip:
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		err = extractor.extractZip(file, info.Size())
+		if err != nil {
+			return err
+		}
+	case isGzip:
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+
+		err = extractor.extractTar(gzipReader)
+		if err != nil {
+			return err
+		}
+	default:
+		err = extractor.extractTar(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	return extractor.finish()
+}
+
+/*
+ChangeKind is the kind of change reported by a ChangeEvent.
+*/
+type ChangeKind int
+
+const (
+	// ChangeCreate indicates that a path was created.
+	ChangeCreate ChangeKind = iota
+
+	// ChangeModify indicates that the size or modification time of a path changed.
+	ChangeModify
+
+	// ChangeDelete indicates that a path was removed.
+	ChangeDelete
+)
+
+/*
+String returns the lowercase name of the change kind.
+*/
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeCreate:
+		return "create"
+	case ChangeModify:
+		return "modify"
+	case ChangeDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+/*
+ChangeEvent is a change detected by Watch.
+*/
+type ChangeEvent struct {
+
+	// Path is the changed path.
+	Path *Path
+
+	// Kind is the kind of change.
+	Kind ChangeKind
+}
+
+/*
+Watch polls this Path in the passed interval and emits events for changes.
+If this Path is a directory, its direct children are watched as well.
+Modifications are detected by size and modification time and are not
+reported for directories, whose modification times change along with their children.
+
+Changes between two polls are coalesced, so that e.g. a file created and removed
+between two polls is not reported. Non-positive intervals default to one second.
+
+The returned function stops watching and closes the channel. Events have
+to be received for the watcher to continue polling.
+*/
+func (p *Path) Watch(interval time.Duration) (<-chan ChangeEvent, func()) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	events := make(chan ChangeEvent)
+	done := make(chan struct{})
+	var once sync.Once
+
+	// the initial snapshot is taken before returning, so that no changes are missed
+	previous := pollSnapshot(p.path, false, nil)
+
+	go func() {
+		defer close(events)
+
+		pollChanges(previous, p.path, false, nil, interval, done, func(event FsEvent) bool {
+			select {
+			case events <- event.ChangeEvent:
+				return true
+			case <-done:
+				return false
+			}
+		})
+	}()
+
+	return events, func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+/*
+FsEvent is a change detected by WatchTree.
+*/
+type FsEvent struct {
+	ChangeEvent
+
+	// IsDir reports whether the changed path is, or was before its removal, a directory.
+	IsDir bool
+
+	// Err is set if watching stopped because of an error. Such an event is
+	// the last one before the channel is closed and its other fields are empty.
+	Err error
+}
+
+/*
+WatchOptions configures WatchTree.
+*/
+type WatchOptions struct {
+
+	// Interval is the polling interval. Defaults to one second.
+	Interval time.Duration
+
+	// Exclude contains patterns of paths to exclude, as in GlobOptions.
+	Exclude []string
+
+	// ForcePolling forces polling, even if a native backend is available.
+	ForcePolling bool
+}
+
+/*
+WatchTree watches this directory tree until ctx is done and emits events for changes.
+The channel is closed when watching stops.
+
+By default, the tree is polled as in Watch, but recursively. When built with the
+"pathlib_inotify" build tag on Linux, inotify is used instead, which reports
+changes as they happen without coalescing them. Then, a modification can be reported
+multiple times, and changes within newly created directories may be reported
+as their creation only.
+
+Events have to be received for the watcher to continue. If the native backend fails,
+e.g. because its event queue overflowed or this directory was removed or moved,
+an event containing the error is emitted before the channel is closed.
+*/
+func (p *Path) WatchTree(ctx context.Context, opts WatchOptions) (<-chan FsEvent, error) {
+	if !p.IsDir() {
+		return nil, errors.New("this path is not a directory")
+	}
+
+	excludes, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan FsEvent)
+
+	// emit sends an event unless ctx is done
+	emit := func(event FsEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if !opts.ForcePolling {
+		watch, ok, err := watchTreeNative(p.path, excludes)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			go func() {
+				defer close(events)
+				watch(ctx.Done(), emit)
+			}()
+
+			return events, nil
+		}
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	previous := pollSnapshot(p.path, true, excludes)
+
+	go func() {
+		defer close(events)
+		pollChanges(previous, p.path, true, excludes, interval, ctx.Done(), emit)
+	}()
+
+	return events, nil
+}
+
+/*
+SwapDirs replaces the current directory with the staged directory.
+
+If current is a symbolic link, the link is atomically flipped to point to staged.
+Otherwise, current is moved aside and staged is renamed to current.
+If current does not exist, staged is simply renamed to current.
+
+If keepOld is true, the previous directory is kept and its Path is returned,
+else it is removed and nil is returned.
+*/
+func SwapDirs(current *Path, staged *Path, keepOld bool) (*Path, error) {
+	if !staged.IsDir() {
+		return nil, errors.New("staged path is not a directory")
+	}
+
+	currentInfo, err := os.Lstat(current.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, os.Rename(staged.path, current.path)
+		}
+
+		return nil, err
+	}
+
+	var old *Path
+	if currentInfo.Mode()&os.ModeSymlink != 0 {
+		old, err = swapSymlink(current, staged)
+	} else {
+		old, err = swapRename(current, staged)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if keepOld {
+		return old, nil
+	}
+
+	return nil, os.RemoveAll(old.path)
+}
+
+/*
+IgnoreMatcher matches paths against a list of .gitignore-style rules.
+
+Create a new instance using NewIgnoreMatcher or NewIgnoreMatcherFromFile.
+*/
+type IgnoreMatcher struct {
+
+	// The directory the rules are relative to.
+	base *Path
+
+	// The parsed rules in their original order.
+	rules []ignoreRule
+}
+
+/*
+ignoreRule is a single parsed rule of an IgnoreMatcher.
+*/
+type ignoreRule struct {
+
+	// The pattern segments, matched using matchSegments.
+	segments []string
+
+	// Whether a match re-includes a previously ignored path.
+	negate bool
+
+	// Whether the rule only matches directories.
+	dirOnly bool
+}
+
+/*
+NewIgnoreMatcher parses the passed .gitignore-style rules relative to the base directory.
+
+The following rule syntax is supported:
+  - blank lines and lines starting with '#' are ignored
+  - a leading '!' negates the rule and re-includes matching paths
+  - a trailing '/' only matches directories
+  - rules containing a '/' at the beginning or in the middle are anchored to
+    the base directory, other rules match at any depth
+  - '**' segments match zero or more directories
+  - a leading backslash escapes '#' and '!'
+
+The last matching rule decides whether a path is ignored. Paths within
+ignored directories are always ignored.
+*/
+func NewIgnoreMatcher(base *Path, rules []string) (*IgnoreMatcher, error) {
+	matcher := &IgnoreMatcher{base: base.Copy()}
+
+	for _, line := range rules {
+		rule, ok, err := parseIgnoreRule(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			matcher.rules = append(matcher.rules, rule)
+		}
+	}
+
+	return matcher, nil
+}
+
+/*
+NewIgnoreMatcherFromFile reads .gitignore-style rules from the passed file.
+The rules are relative to the file's parent directory.
+*/
+func NewIgnoreMatcherFromFile(file *Path) (*IgnoreMatcher, error) {
+	content, err := os.ReadFile(file.path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	return NewIgnoreMatcher(file.Parent(), lines)
+}
+
+/*
+Match returns whether the passed Path is ignored. The matching is purely lexical,
+thus it has to be passed whether the Path is a directory.
+Paths outside the matcher's base directory are never ignored.
+*/
+func (m *IgnoreMatcher) Match(p *Path, isDir bool) bool {
+	base, path := m.base, p
+	if base.IsAbsolute() != path.IsAbsolute() {
+		var err error
+		if base, err = base.Absolute(); err != nil {
+			return false
+		}
+		if path, err = path.Absolute(); err != nil {
+			return false
+		}
+	}
+
+	rel, err := path.RelativeTo(base)
+	if err != nil || rel.path == "." || rel.Parts()[0] == ".." {
+		return false
+	}
+
+	segments := splitPattern(rel.path)
+
+	// a path is ignored if one of its parent directories is ignored
+	for i := 1; i < len(segments); i++ {
+		if m.matchRules(segments[:i], true) {
+			return true
+		}
+	}
+
+	return m.matchRules(segments, isDir)
+}
+
+/*
+matchRules evaluates all rules against the passed relative path segments.
+*/
+func (m *IgnoreMatcher) matchRules(segments []string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if matchSegments(rule.segments, segments, false) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+/*
+IsIgnored returns whether this Path is ignored by the passed IgnoreMatcher.
+The filesystem is queried to decide whether this Path is a directory.
+*/
+func (p *Path) IsIgnored(m *IgnoreMatcher) bool {
+	return m.Match(p, p.IsDir())
+}
+
+/*
+clean cleans up this Path using the default options.
+
+This function utilizes filepath.Clean.
+*/
+func cleanPathString(p string) string {
+	return cleanPathStringWith(p, DefaultParseOptions())
+}
+
+/*
+cleanPathStringWith cleans up a path string using the passed options.
+
+This function utilizes filepath.Clean.
+*/
+func cleanPathStringWith(p string, opts ParseOptions) string {
+	dirty := p
+	if opts.TrimSpace {
+		dirty = strings.TrimSpace(dirty)
+	}
+
+	if opts.UnescapeWhitespace {
+		// remove whitespace escape characters during internal representation
+		dirty = strings.ReplaceAll(dirty, "\\ ", " ")
+	}
+
+	if opts.BackslashSeparators && filepath.Separator != '\\' {
+		// replace all other '\\' characters with separator
+		dirty = strings.ReplaceAll(dirty, "\\", pathSeparator)
+	}
+
+	cleanPath := filepath.Clean(dirty)
+	return normalizeString(cleanPath, opts.Normalization)
+}
+
+/*
+pathCheck is a lower level Path existence checker.
+It returns 0 if the path does not exist, 2 if it's a file and 2 if it's a directory.
+*/
+func pathCheck(p Path) int {
+	fileInfo, err := os.Stat(p.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pathCheckNoExist
+		}
+	}
+
+	if fileInfo == nil {
+		return pathCheckNoExist
+	}
+
+	if fileInfo.IsDir() {
+		return pathCheckDir
+	}
+
+	return pathCheckFile
+}
+
+/*
+flipCase is a utility function that takes the first character
+and flips it's case. The leftover characters are appended.
+This results in a string which is different from the original which can be used for
+e.g. case sensitivity (in)variance.
+*/
+func flipCase(s string) string {
+	if s == "" {
+		return s
+	}
+	firstChar := string(s[0])
+	if strings.ToLower(firstChar) == firstChar {
+		return strings.ToUpper(firstChar) + s[1:]
+	}
+	return strings.ToLower(firstChar) + s[1:]
+}
+
+/*
+nativeGlob is a wrapper function for Go's filepath.Glob.
+It checks if the passed Path exists and returns the raw matches or errors.
+Patterns containing '**' segments are matched using globRecursive.
+
+Returns an error if pattern is an empty string.
+
+filepath.Glob ignores IO errors.
+*/
+func nativeGlob(p *Path, pattern string) ([]string, error) {
+	err := checkGlob(p, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasRecursiveSegment(pattern) {
+		var matches []string
+		err := globRecursive(p.path, pattern, func(match string) bool {
+			matches = append(matches, match)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return matches, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(p.path, pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+/*
+checkGlob checks whether the passed pattern is not empty
+and the passed Path is an existing directory.
+*/
+func checkGlob(p *Path, pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("pattern must not be empty")
+	}
 
 	if !p.Exists() {
-		return nil, errors.New("this Path does not exist")
+		return errors.New("this Path does not exist")
+	}
+
+	if !p.IsDir() {
+		return errors.New("this path is not a directory")
+	}
+
+	return nil
+}
+
+/*
+splitPattern splits a glob pattern into its segments.
+Both forward slashes and the OS-specific separator are treated as separators.
+*/
+func splitPattern(pattern string) []string {
+	return strings.FieldsFunc(strings.TrimSpace(pattern), func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
+}
+
+/*
+repeatsMatches returns whether the passed pattern segments may match the same path multiple times,
+which is the case for multiple '**' segments and '..' segments.
+*/
+func repeatsMatches(segments []string) bool {
+	recursive := 0
+	for _, segment := range segments {
+		if segment == ".." {
+			return true
+		}
+
+		if segment == "**" {
+			recursive++
+		}
+	}
+
+	return recursive > 1
+}
+
+/*
+hasRecursiveSegment returns whether the passed pattern contains a '**' segment.
+*/
+func hasRecursiveSegment(pattern string) bool {
+	for _, segment := range splitPattern(pattern) {
+		if segment == "**" {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+hasGlobMeta returns whether the passed pattern segment contains any
+characters that are special to filepath.Match.
+*/
+func hasGlobMeta(segment string) bool {
+	magicChars := `*?[\`
+	if runtime.GOOS == "windows" {
+		magicChars = `*?[`
+	}
+
+	return strings.ContainsAny(segment, magicChars)
+}
+
+/*
+globWalker matches pattern segments against the filesystem.
+*/
+type globWalker struct {
+
+	// Whether symbolic links to directories are followed while matching '**' segments.
+	followSymlinks bool
+
+	// Whether segments are matched ignoring case.
+	caseInsensitive bool
+
+	// Optional function to exclude matches. Excluded directories are not traversed.
+	exclude func(path string) bool
+
+	// Resolved directories that have been visited while following symbolic links.
+	visited map[string]struct{}
+
+	// Paths that have already been reported, if the pattern may repeat matches.
+	seen map[string]struct{}
+
+	// Function that is called for every match. The traversal stops if it returns false.
+	fn func(match string) bool
+}
+
+/*
+globRecursive calls fn for every path below root that matches the passed pattern.
+A '**' segment matches zero or more directories, every other segment is matched
+using filepath.Match. Symbolic links to directories are not followed while
+matching '**' segments. The traversal stops if fn returns false.
+
+IO errors are ignored, as in filepath.Glob. Every match is reported only once.
+*/
+func globRecursive(root string, pattern string, fn func(match string) bool) error {
+	return (&globWalker{fn: fn}).glob(root, pattern)
+}
+
+/*
+glob validates the passed pattern and matches it below root.
+*/
+func (w *globWalker) glob(root string, pattern string) error {
+	segments := splitPattern(pattern)
+	for _, segment := range segments {
+		_, err := filepath.Match(segment, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	// matches can only repeat if multiple '**' segments match the same directories
+	// or '..' segments lead back to them, so only then every match is remembered
+	if repeatsMatches(segments) {
+		w.seen = make(map[string]struct{})
+	}
+
+	w.visited = make(map[string]struct{})
+	w.globSegments(root, segments)
+
+	return nil
+}
+
+/*
+report passes a match to fn, unless it has already been reported.
+*/
+func (w *globWalker) report(match string) bool {
+	if w.seen == nil {
+		return w.fn(match)
+	}
+
+	if _, ok := w.seen[match]; ok {
+		return true
+	}
+
+	w.seen[match] = struct{}{}
+	return w.fn(match)
+}
+
+/*
+descend returns whether a '**' segment may descend into the passed directory entry.
+*/
+func (w *globWalker) descend(dir string, entry fs.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+
+	if !w.followSymlinks || entry.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+
+	// only follow links to directories, and each directory only once to prevent cycles
+	resolved, err := filepath.EvalSymlinks(filepath.Join(dir, entry.Name()))
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	if _, ok := w.visited[resolved]; ok {
+		return false
+	}
+
+	w.visited[resolved] = struct{}{}
+	return true
+}
+
+/*
+globSegments matches the passed pattern segments against the children of dir.
+It returns false if the traversal was stopped by fn.
+*/
+func (w *globWalker) globSegments(dir string, segments []string) bool {
+	if w.exclude != nil && w.exclude(dir) {
+		return true
+	}
+
+	if len(segments) == 0 {
+		return w.report(dir)
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment == "**" {
+		// '**' only matches directories
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return true
+		}
+
+		// match zero directories
+		if !w.globSegments(dir, rest) {
+			return false
+		}
+
+		// match one or more directories
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if w.descend(dir, entry) && !w.globSegments(filepath.Join(dir, entry.Name()), segments) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	// literal segments can only be looked up directly if case matters
+	if !hasGlobMeta(segment) && !w.caseInsensitive {
+		child := filepath.Join(dir, segment)
+		if _, err := os.Lstat(child); err != nil {
+			return true
+		}
+
+		return w.globSegments(child, rest)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if matchSegment(segment, entry.Name(), w.caseInsensitive) && !w.globSegments(filepath.Join(dir, entry.Name()), rest) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+equalFileContents compares the contents of two files chunk by chunk.
+*/
+func equalFileContents(first string, second string) (bool, error) {
+	firstFile, err := os.Open(first)
+	if err != nil {
+		return false, err
+	}
+	defer firstFile.Close()
+
+	secondFile, err := os.Open(second)
+	if err != nil {
+		return false, err
+	}
+	defer secondFile.Close()
+
+	const chunkSize = 32 * 1024
+	firstBuf := make([]byte, chunkSize)
+	secondBuf := make([]byte, chunkSize)
+
+	for {
+		firstN, firstErr := io.ReadFull(firstFile, firstBuf)
+		secondN, secondErr := io.ReadFull(secondFile, secondBuf)
+
+		if firstErr != nil && firstErr != io.EOF && firstErr != io.ErrUnexpectedEOF {
+			return false, firstErr
+		}
+		if secondErr != nil && secondErr != io.EOF && secondErr != io.ErrUnexpectedEOF {
+			return false, secondErr
+		}
+
+		if !bytes.Equal(firstBuf[:firstN], secondBuf[:secondN]) {
+			return false, nil
+		}
+
+		// a short read means the end of the file was reached
+		if firstErr != nil || secondErr != nil {
+			return firstErr != nil && secondErr != nil, nil
+		}
+	}
+}
+
+/*
+swapRename moves the current directory aside and renames staged to current.
+If the second rename fails, the current directory is moved back.
+It returns the Path the current directory was moved to.
+*/
+func swapRename(current *Path, staged *Path) (*Path, error) {
+	old := current.WithName(fmt.Sprintf("%s.old-%d", current.Base(), time.Now().UnixNano()))
+
+	err := os.Rename(current.path, old.path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(staged.path, current.path)
+	if err != nil {
+		// try to restore the previous state
+		_ = os.Rename(old.path, current.path)
+		return nil, err
+	}
+
+	return old, nil
+}
+
+/*
+swapSymlink atomically replaces the symbolic link current with a link to staged.
+The link is created next to current and renamed over it.
+It returns the Path the link pointed to before.
+*/
+func swapSymlink(current *Path, staged *Path) (*Path, error) {
+	oldTarget, err := os.Readlink(current.path)
+	if err != nil {
+		return nil, err
+	}
+
+	old := NewPath(oldTarget)
+	if old.IsRelative() {
+		old = current.Parent().Join(old)
+	}
+
+	target, err := linkTarget(current.Parent(), staged)
+	if err != nil {
+		return nil, err
+	}
+
+	tempLink := current.WithName(fmt.Sprintf(".%s.swap-%d", current.Base(), time.Now().UnixNano()))
+	err = os.Symlink(target, tempLink.path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(tempLink.path, current.path)
+	if err != nil {
+		_ = os.Remove(tempLink.path)
+		return nil, err
+	}
+
+	return old, nil
+}
+
+/*
+linkTarget returns the target string for a symbolic link within dir pointing to target.
+The target is made relative to dir if possible, so that the link survives relocations.
+*/
+func linkTarget(dir *Path, target *Path) (string, error) {
+	absDir, err := dir.Absolute()
+	if err != nil {
+		return "", err
+	}
+
+	absTarget, err := target.Absolute()
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(absDir.path, absTarget.path)
+	if err != nil {
+		return absTarget.path, nil
+	}
+
+	return rel, nil
+}
+
+/*
+firstMatching returns the first Path for which the predicate returns true.
+*/
+func firstMatching(paths []*Path, predicate func(*Path) bool) (*Path, error) {
+	for _, path := range paths {
+		if predicate(path) {
+			return path, nil
+		}
+	}
+
+	return nil, fmt.Errorf("none of the passed paths exist: %w", os.ErrNotExist)
+}
+
+/*
+expandTemplate replaces all tokens of a template string.
+The time used for time-based tokens is passed to make all tokens of a
+single expansion consistent.
+*/
+func expandTemplate(template string, vars map[string]string, now time.Time) (string, error) {
+	var builder strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		char := template[i]
+
+		switch {
+		case char == '{' && strings.HasPrefix(template[i:], "{{"):
+			builder.WriteByte('{')
+			i++
+		case char == '}' && strings.HasPrefix(template[i:], "}}"):
+			builder.WriteByte('}')
+			i++
+		case char == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("unclosed token at position %d", i)
+			}
+
+			value, err := templateToken(template[i+1:i+end], vars, now)
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(value)
+			i += end
+		case char == '}':
+			return "", fmt.Errorf("unexpected '}' at position %d", i)
+		default:
+			builder.WriteByte(char)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+/*
+templateToken returns the value of a single template token.
+*/
+func templateToken(token string, vars map[string]string, now time.Time) (string, error) {
+	if value, ok := vars[token]; ok {
+		return value, nil
+	}
+
+	name, layout, hasLayout := strings.Cut(token, ":")
+	switch name {
+	case "date":
+		if !hasLayout {
+			layout = "2006-01-02"
+		}
+		return now.Format(layout), nil
+	case "time":
+		if !hasLayout {
+			layout = "15-04-05"
+		}
+		return now.Format(layout), nil
+	}
+
+	switch token {
+	case "unix":
+		return strconv.FormatInt(now.Unix(), 10), nil
+	case "pid":
+		return strconv.Itoa(os.Getpid()), nil
+	case "host", "hostname":
+		return os.Hostname()
+	case "uuid":
+		return newUUID()
+	}
+
+	return "", fmt.Errorf("unknown token '%s'", token)
+}
+
+/*
+newUUID returns a random version 4 UUID.
+*/
+func newUUID() (string, error) {
+	var uuid [16]byte
+	_, err := rand.Read(uuid[:])
+	if err != nil {
+		return "", err
+	}
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x40 // version 4
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
+
+/*
+copyTree recursively copies source to dest, which must not exist.
+File modes, modification times and symbolic links are preserved.
+*/
+func copyTree(source string, dest string) error {
+	type copiedDir struct {
+		path string
+		info fs.FileInfo
+	}
+
+	var dirs []copiedDir
+
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			dirs = append(dirs, copiedDir{path: target, info: info})
+		}
+
+		return copyEntry(path, target, info)
+	})
+	if err != nil {
+		return err
+	}
+
+	// apply in reverse order so that children are handled before their parents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		err = applyDirInfo(dirs[i].path, dirs[i].info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+copyEntry copies a single entry to dest, which must not exist.
+Symbolic links are recreated and regular files are copied with their mode and modification time.
+Directories are created writable without their children to allow copying them,
+the original mode and times have to be applied afterward using applyDirInfo.
+*/
+func copyEntry(source string, dest string, info fs.FileInfo) error {
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		linkTarget, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(linkTarget, dest)
+	case info.IsDir():
+		return os.Mkdir(dest, 0700)
+	case info.Mode().IsRegular():
+		err := copyFile(source, dest, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(dest, info.ModTime(), info.ModTime())
+	default:
+		return fmt.Errorf("cannot copy special file '%s'", source)
+	}
+}
+
+/*
+applyDirInfo applies the permissions and modification time of info to the directory at path.
+*/
+func applyDirInfo(path string, info fs.FileInfo) error {
+	err := os.Chmod(path, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	return os.Chtimes(path, info.ModTime(), info.ModTime())
+}
+
+/*
+copyFile copies the contents of a regular file to a new file with the passed permissions.
+*/
+func copyFile(source string, dest string, perm os.FileMode) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(destFile, sourceFile)
+	if err != nil {
+		_ = destFile.Close()
+		return err
+	}
+
+	return destFile.Close()
+}
+
+/*
+matchSegments lexically matches path segments against pattern segments.
+A '**' pattern segment matches zero or more path segments.
+The pattern segments are required to be valid.
+*/
+func matchSegments(patternSegments []string, pathSegments []string, caseInsensitive bool) bool {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0
+	}
+
+	if patternSegments[0] == "**" {
+		for skip := 0; skip <= len(pathSegments); skip++ {
+			if matchSegments(patternSegments[1:], pathSegments[skip:], caseInsensitive) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(pathSegments) == 0 {
+		return false
+	}
+
+	return matchSegment(patternSegments[0], pathSegments[0], caseInsensitive) &&
+		matchSegments(patternSegments[1:], pathSegments[1:], caseInsensitive)
+}
+
+/*
+matchSegment matches a single path segment against a pattern segment using filepath.Match.
+If caseInsensitive is true, they are matched using matchSegmentFold instead.
+*/
+func matchSegment(pattern string, name string, caseInsensitive bool) bool {
+	if caseInsensitive {
+		return matchSegmentFold(pattern, name)
+	}
+
+	matched, _ := filepath.Match(pattern, name)
+	return matched
+}
+
+/*
+foldedRune is a rune of a case folded name. The first rune of the folding of
+an original rune additionally holds the original rune and the length of its folding.
+*/
+type foldedRune struct {
+	folded   rune
+	original rune
+	length   int
+}
+
+/*
+matchSegmentFold matches a single path segment against a pattern segment like filepath.Match,
+but ignoring case as in EqualsFold. The name and the literal runes of the pattern are case folded,
+while character classes and '?' match a rune if they match any of its case variants.
+Invalid patterns never match.
+*/
+func matchSegmentFold(pattern string, name string) bool {
+	var folded []foldedRune
+	for _, r := range name {
+		runes := []rune(foldCase(string(r)))
+		for i, f := range runes {
+			if i == 0 {
+				folded = append(folded, foldedRune{folded: f, original: r, length: len(runes)})
+			} else {
+				folded = append(folded, foldedRune{folded: f})
+			}
+		}
+	}
+
+	return matchRunesFold([]rune(pattern), folded)
+}
+
+/*
+matchRunesFold matches the case folded runes of a name against a pattern for matchSegmentFold.
+*/
+func matchRunesFold(pattern []rune, name []foldedRune) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := 0; i <= len(name); i++ {
+				if matchRunesFold(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?', '[':
+			contains, rest, ok := func(r rune) bool { return true }, pattern[1:], true
+			if pattern[0] == '[' {
+				contains, rest, ok = parseClassFold(pattern[1:])
+			}
+
+			if !ok || len(name) == 0 {
+				return false
+			}
+
+			// a rune folded into multiple runes can be matched as a whole
+			if name[0].length > 1 && contains(name[0].original) && matchRunesFold(rest, name[name[0].length:]) {
+				return true
+			}
+
+			if !contains(name[0].folded) {
+				return false
+			}
+			pattern, name = rest, name[1:]
+		default:
+			r := pattern[0]
+			if r == '\\' && runtime.GOOS != "windows" {
+				if len(pattern) < 2 {
+					return false
+				}
+				pattern = pattern[1:]
+				r = pattern[0]
+			}
+
+			for _, f := range foldCase(string(r)) {
+				if len(name) == 0 || name[0].folded != f {
+					return false
+				}
+				name = name[1:]
+			}
+			pattern = pattern[1:]
+		}
+	}
+
+	return len(name) == 0
+}
+
+/*
+parseClassFold parses a character class following its opening bracket as in filepath.Match.
+It returns a function reporting whether the class contains any case variant of a rune,
+and the rest of the pattern. The boolean return value is false if the class is invalid.
+*/
+func parseClassFold(class []rune) (func(r rune) bool, []rune, bool) {
+	negated := len(class) > 0 && class[0] == '^'
+	if negated {
+		class = class[1:]
+	}
+
+	// classRune returns the next, possibly escaped, rune of the class
+	classRune := func() (rune, bool) {
+		if len(class) == 0 || class[0] == '-' || class[0] == ']' {
+			return 0, false
+		}
+
+		if class[0] == '\\' && runtime.GOOS != "windows" {
+			class = class[1:]
+			if len(class) == 0 {
+				return 0, false
+			}
+		}
+
+		r := class[0]
+		class = class[1:]
+		return r, true
+	}
+
+	var ranges [][2]rune
+	for len(class) == 0 || class[0] != ']' || len(ranges) == 0 {
+		lo, ok := classRune()
+		if !ok {
+			return nil, nil, false
+		}
+
+		hi := lo
+		if len(class) > 0 && class[0] == '-' {
+			class = class[1:]
+			hi, ok = classRune()
+			if !ok {
+				return nil, nil, false
+			}
+		}
+
+		ranges = append(ranges, [2]rune{lo, hi})
+	}
+
+	contains := func(r rune) bool {
+		// the case variants of a rune form an orbit of unicode.SimpleFold
+		variant := r
+		for {
+			for _, span := range ranges {
+				if span[0] <= variant && variant <= span[1] {
+					return !negated
+				}
+			}
+
+			variant = unicode.SimpleFold(variant)
+			if variant == r {
+				return negated
+			}
+		}
+	}
+
+	return contains, class[1:], true
+}
+
+/*
+parseIgnoreRule parses a single .gitignore-style rule.
+The boolean return value is false if the line does not contain a rule.
+*/
+func parseIgnoreRule(line string) (ignoreRule, bool, error) {
+	rule := ignoreRule{}
+
+	// trailing whitespace is removed unless it's escaped
+	line = strings.TrimSuffix(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false, nil
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\#") || strings.HasPrefix(line, "\\!") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	anchored := strings.Contains(line, "/")
+
+	for _, segment := range strings.Split(line, "/") {
+		if segment == "" {
+			continue
+		}
+
+		_, err := filepath.Match(segment, "")
+		if err != nil {
+			return rule, false, fmt.Errorf("invalid ignore rule '%s': %w", line, err)
+		}
+
+		rule.segments = append(rule.segments, segment)
+	}
+
+	if len(rule.segments) == 0 {
+		return rule, false, nil
+	}
+
+	if !anchored {
+		rule.segments = append([]string{"**"}, rule.segments...)
+	}
+
+	// a trailing '**' matches everything inside, but not the directory itself
+	if rule.segments[len(rule.segments)-1] == "**" {
+		rule.segments = append(rule.segments, "*")
+	}
+
+	return rule, true, nil
+}
+
+/*
+compileExcludes splits the passed exclude patterns into segments and validates them.
+*/
+func compileExcludes(patterns []string) ([][]string, error) {
+	excludes := make([][]string, len(patterns))
+	for i, pattern := range patterns {
+		excludes[i] = splitPattern(pattern)
+		for _, segment := range excludes[i] {
+			_, err := filepath.Match(segment, "")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return excludes, nil
+}
+
+/*
+isExcluded returns whether the passed path, relative to root,
+or one of its parents matches any of the exclude pattern segments.
+*/
+func isExcluded(root string, path string, excludes [][]string, caseInsensitive bool) bool {
+	if len(excludes) == 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	segments := splitPattern(rel)
+	for i := 1; i <= len(segments); i++ {
+		for _, exclude := range excludes {
+			if matchSegments(exclude, segments[:i], caseInsensitive) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/*
+newPathInfo converts os.FileInfo into a PathInfo.
+*/
+func newPathInfo(info os.FileInfo) PathInfo {
+	uid, gid, ok := fileOwner(info)
+	if !ok {
+		uid, gid = -1, -1
+	}
+
+	return PathInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		Uid:     uid,
+		Gid:     gid,
+	}
+}
+
+/*
+isDotfile returns whether the base of the passed path starts with a dot.
+The special entries '.' and '..' are no dotfiles.
+*/
+func isDotfile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, ".") && base != "." && base != ".."
+}
+
+/*
+treeSize recursively sums up the sizes of all entries below root.
+Resolved directories are tracked in visited to prevent counting them twice
+while following symbolic links.
+*/
+func treeSize(root string, opts TreeSizeOptions, visited map[string]struct{}) (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !opts.FollowSymlinks {
+			total += entrySize(info, opts.DiskUsage)
+			return nil
+		}
+
+		if info.IsDir() {
+			resolved, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return err
+			}
+
+			// skip directories that were already counted through a link
+			if _, ok := visited[resolved]; ok {
+				return fs.SkipDir
+			}
+			visited[resolved] = struct{}{}
+		}
+
+		if info.Mode()&fs.ModeSymlink == 0 {
+			total += entrySize(info, opts.DiskUsage)
+			return nil
+		}
+
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			// broken links do not contribute to the size
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		targetInfo, err := os.Stat(resolved)
+		if err != nil {
+			return err
+		}
+
+		if !targetInfo.IsDir() {
+			total += entrySize(targetInfo, opts.DiskUsage)
+			return nil
+		}
+
+		// the directory is marked as visited by the recursive call
+		size, err := treeSize(resolved, opts, visited)
+		total += size
+		return err
+	})
+
+	return total, err
+}
+
+/*
+entrySize returns the size of a single filesystem entry, as counted by TreeSize.
+*/
+func entrySize(info os.FileInfo, diskUsage bool) int64 {
+	if diskUsage {
+		if size, ok := fileDiskSize(info); ok {
+			return size
+		}
+	}
+
+	if !info.Mode().IsRegular() {
+		return 0
+	}
+
+	return info.Size()
+}
+
+/*
+hasModeType returns whether the passed Path exists and its
+file mode has all the passed type bits set.
+*/
+func hasModeType(p *Path, modeType os.FileMode) bool {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&modeType == modeType
+}
+
+/*
+treeEntries returns the file modes of all entries below root, keyed by their relative path.
+Excluded entries are skipped.
+*/
+func treeEntries(root string, excludes [][]string) (map[string]os.FileMode, error) {
+	entries := make(map[string]os.FileMode)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			if !d.IsDir() {
+				return errors.New("this path is not a directory")
+			}
+
+			return nil
+		}
+
+		if isExcluded(root, path, excludes, false) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		entries[rel] = d.Type()
+		return nil
+	})
+
+	return entries, err
+}
+
+/*
+sortedKeys returns the keys of the passed map in ascending order.
+*/
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+/*
+archiveEntry is a single entry collected by archiveEntries.
+*/
+type archiveEntry struct {
+	path string
+	name string
+	info fs.FileInfo
+}
+
+/*
+archiveEntries collects the entries to archive from root in lexical order.
+The entries are named by their slash-separated path relative to root, or by root's name if it is a file.
+The archive itself is skipped in case it is written into root.
+*/
+func archiveEntries(root string, archive string, opts ArchiveOptions) ([]archiveEntry, error) {
+	includes, err := compileExcludes(opts.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := compileExcludes(opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	rootInfo, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !rootInfo.IsDir() {
+		return []archiveEntry{{path: root, name: filepath.Base(root), info: rootInfo}}, nil
+	}
+
+	// the archive's path relative to root is used to skip it
+	archiveRel := ""
+	absRoot, rootErr := filepath.Abs(root)
+	absArchive, archiveErr := filepath.Abs(archive)
+	if rootErr == nil && archiveErr == nil {
+		archiveRel, _ = filepath.Rel(absRoot, absArchive)
+	}
+
+	var entries []archiveEntry
+	included := make(map[string]bool)
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == root {
+			return nil
+		}
+
+		if isExcluded(root, path, excludes, false) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == archiveRel {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := archiveEntry{path: path, name: filepath.ToSlash(rel), info: info}
+
+		// inclusions use the same prefix matching as exclusions
+		if len(includes) == 0 || isExcluded(root, path, includes, false) {
+			included[entry.name] = true
+
+			// parent directories are required for extraction
+			for parent := filepath.Dir(rel); parent != "."; parent = filepath.Dir(parent) {
+				included[filepath.ToSlash(parent)] = true
+			}
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []archiveEntry
+	for _, entry := range entries {
+		if included[entry.name] {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nil
+}
+
+/*
+writeArchive creates the file at dest and writes into it using write.
+On failure, the file is removed.
+*/
+func writeArchive(dest string, write func(w io.Writer) error) error {
+	file, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if err == nil {
+		err = file.Close()
+	} else {
+		_ = file.Close()
+	}
+
+	if err != nil {
+		_ = os.Remove(dest)
+	}
+
+	return err
+}
+
+/*
+writeZip writes the passed entries as zip archive into w.
+*/
+func writeZip(w io.Writer, entries []archiveEntry) error {
+	zipWriter := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		header, err := zip.FileInfoHeader(entry.info)
+		if err != nil {
+			return err
+		}
+
+		header.Name = entry.name
+		switch {
+		case entry.info.IsDir():
+			header.Name += "/"
+		case entry.info.Mode().IsRegular():
+			header.Method = zip.Deflate
+		case entry.info.Mode()&fs.ModeSymlink == 0:
+			return fmt.Errorf("cannot archive special file '%s'", entry.path)
+		}
+
+		entryWriter, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		err = writeArchiveContent(entryWriter, entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+/*
+writeArchiveContent writes the content of an entry into w.
+The content of a symbolic link is its target, directories have no content.
+*/
+func writeArchiveContent(w io.Writer, entry archiveEntry) error {
+	switch {
+	case entry.info.IsDir():
+		return nil
+	case entry.info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(entry.path)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, filepath.ToSlash(target))
+		return err
+	default:
+		file, err := os.Open(entry.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(w, file)
+		return err
+	}
+}
+
+/*
+writeTar writes the passed entries as tar archive into w.
+*/
+func writeTar(w io.Writer, entries []archiveEntry) error {
+	tarWriter := tar.NewWriter(w)
+
+	for _, entry := range entries {
+		linkTarget := ""
+		if entry.info.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(entry.path)
+			if err != nil {
+				return err
+			}
+			linkTarget = filepath.ToSlash(target)
+		} else if !entry.info.IsDir() && !entry.info.Mode().IsRegular() {
+			return fmt.Errorf("cannot archive special file '%s'", entry.path)
+		}
+
+		header, err := tar.FileInfoHeader(entry.info, linkTarget)
+		if err != nil {
+			return err
+		}
+
+		header.Name = entry.name
+		if entry.info.IsDir() {
+			header.Name += "/"
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if entry.info.Mode().IsRegular() {
+			err = writeArchiveContent(tarWriter, entry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+/*
+archiveExtractor extracts archive entries into dest.
+*/
+type archiveExtractor struct {
+	dest  string
+	dirs  []extractedDir
+	links []extractedLink
+}
+
+/*
+extractedDir is a directory created by archiveExtractor,
+whose mode and modification time are applied when finishing.
+*/
+type extractedDir struct {
+	path string
+	info fs.FileInfo
+}
+
+/*
+extractedLink is a symbolic link of an archive, which is created when finishing
+after all other entries have been extracted.
+*/
+type extractedLink struct {
+	rel    string
+	target string
+}
+
+/*
+extractZip extracts all entries of a zip archive.
+*/
+func (e *archiveExtractor) extractZip(r io.ReaderAt, size int64) error {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range zipReader.File {
+		err = e.extractZipFile(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+extractZipFile extracts a single entry of a zip archive.
+*/
+func (e *archiveExtractor) extractZipFile(file *zip.File) error {
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	info := file.FileInfo()
+	linkTarget := ""
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+		linkTarget = string(target)
+	}
+
+	return e.extract(file.Name, info, linkTarget, content)
+}
+
+/*
+extractTar extracts all entries of a tar archive.
+*/
+func (e *archiveExtractor) extractTar(r io.Reader) error {
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeDir, tar.TypeSymlink:
+			err = e.extract(header.Name, header.FileInfo(), header.Linkname, tarReader)
+			if err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("unsupported archive entry '%s'", header.Name)
+		}
+	}
+}
+
+/*
+extract creates a single entry below dest.
+The name is slash-separated and must not escape dest, and neither must linkTarget for symbolic links.
+*/
+func (e *archiveExtractor) extract(name string, info fs.FileInfo, linkTarget string, content io.Reader) error {
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSuffix(name, "/")))
+	if rel == "." {
+		return nil
+	}
+
+	if !filepath.IsLocal(rel) {
+		return fmt.Errorf("archive entry '%s' escapes the destination", name)
+	}
+
+	// entries below symbolic links could escape through them
+	for parent := filepath.Dir(rel); parent != "."; parent = filepath.Dir(parent) {
+		info, err := os.Lstat(filepath.Join(e.dest, parent))
+		if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("archive entry '%s' is located below a symbolic link", name)
+		}
+	}
+
+	target := filepath.Join(e.dest, rel)
+
+	err := os.MkdirAll(filepath.Dir(target), defaultDirPerm)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		err = os.Mkdir(target, 0700)
+		if errors.Is(err, fs.ErrExist) {
+			existing, statErr := os.Lstat(target)
+			if statErr != nil {
+				return statErr
+			}
+			if !existing.IsDir() {
+				return fmt.Errorf("archive entry '%s' is a directory, but a file exists at its location", name)
+			}
+		} else if err != nil {
+			return err
+		}
+
+		// directories are kept writable until all entries are extracted
+		e.dirs = append(e.dirs, extractedDir{path: target, info: info})
+		return nil
+	case info.Mode()&fs.ModeSymlink != 0:
+		linkTarget = filepath.FromSlash(linkTarget)
+		if isRootedLinkTarget(linkTarget) || !filepath.IsLocal(filepath.Join(filepath.Dir(rel), linkTarget)) {
+			return fmt.Errorf("symbolic link '%s' escapes the destination", name)
+		}
+
+		// links are created last, so that no other entry is written through them
+		e.links = append(e.links, extractedLink{rel: rel, target: linkTarget})
+		return nil
+	case info.Mode().IsRegular():
+		file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(file, content)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		err = file.Close()
+		if err != nil {
+			return err
+		}
+
+		return os.Chtimes(target, info.ModTime(), info.ModTime())
+	default:
+		return fmt.Errorf("unsupported archive entry '%s'", name)
+	}
+}
+
+/*
+finish creates the symbolic links and applies the modes and modification times
+of extracted directories. Links are verified against the extracted tree, as links
+pointing through other links may escape dest although their targets are local.
+If any link escapes dest, all links are removed again and an error is returned.
+*/
+func (e *archiveExtractor) finish() error {
+	for i, link := range e.links {
+		err := os.Symlink(link.target, filepath.Join(e.dest, link.rel))
+		if err != nil {
+			e.removeLinks(e.links[:i])
+			return err
+		}
+	}
+
+	for _, link := range e.links {
+		if !e.resolvesWithin(link) {
+			e.removeLinks(e.links)
+			return fmt.Errorf("symbolic link '%s' escapes the destination", filepath.ToSlash(link.rel))
+		}
+	}
+
+	// apply in reverse order so that children are handled before their parents
+	for i := len(e.dirs) - 1; i >= 0; i-- {
+		err := applyDirInfo(e.dirs[i].path, e.dirs[i].info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+removeLinks removes the passed extracted symbolic links.
+*/
+func (e *archiveExtractor) removeLinks(links []extractedLink) {
+	for _, link := range links {
+		_ = os.Remove(filepath.Join(e.dest, link.rel))
+	}
+}
+
+/*
+resolvesWithin returns whether an extracted symbolic link resolves to a location within dest.
+Its target is resolved element by element against the extracted tree, following
+all symbolic links on the way, like the operating system does.
+*/
+func (e *archiveExtractor) resolvesWithin(link extractedLink) bool {
+	// maxLinks limits the number of followed links, e.g. for link cycles
+	const maxLinks = 255
+
+	var resolved []string
+	pending := append(pathElements(filepath.Dir(link.rel)), pathElements(link.target)...)
+	followed := 0
+
+	for len(pending) > 0 {
+		element := pending[0]
+		pending = pending[1:]
+
+		switch element {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return false
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+
+		resolved = append(resolved, element)
+		current := filepath.Join(append([]string{e.dest}, resolved...)...)
+
+		info, err := os.Lstat(current)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		followed++
+		if followed > maxLinks {
+			return false
+		}
+
+		target, err := os.Readlink(current)
+		if err != nil || isRootedLinkTarget(target) {
+			return false
+		}
+
+		resolved = resolved[:len(resolved)-1]
+		pending = append(pathElements(target), pending...)
+	}
+
+	return true
+}
+
+/*
+isRootedLinkTarget returns whether a symbolic link target is absolute,
+rooted or has a volume name, i.e. whether it is not relative to the link.
+*/
+func isRootedLinkTarget(target string) bool {
+	return filepath.IsAbs(target) || filepath.VolumeName(target) != "" || strings.HasPrefix(target, pathSeparator)
+}
+
+/*
+snapshotEntry is the state of a single path in a poll snapshot.
+*/
+type snapshotEntry struct {
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+/*
+pollSnapshot returns the state of root and, if it is a directory, its children.
+If recursive is true, all entries below root are included. Excluded entries
+and entries that cannot be read, e.g. because they were removed meanwhile, are skipped.
+Symbolic links are not followed, except for root.
+*/
+func pollSnapshot(root string, recursive bool, excludes [][]string) map[string]snapshotEntry {
+	snapshot := make(map[string]snapshotEntry)
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return snapshot
+	}
+	snapshot[root] = snapshotEntry{size: info.Size(), modTime: info.ModTime(), isDir: info.IsDir()}
+
+	if !info.IsDir() {
+		return snapshot
+	}
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+
+		if isExcluded(root, path, excludes, false) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := d.Info()
+		if err == nil {
+			snapshot[path] = snapshotEntry{size: info.Size(), modTime: info.ModTime(), isDir: info.IsDir()}
+		}
+
+		if d.IsDir() && !recursive {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+
+	return snapshot
+}
+
+/*
+pollChanges polls root in the passed interval, starting from the previous snapshot,
+and passes all changes to emit until done is closed or emit returns false.
+*/
+func pollChanges(previous map[string]snapshotEntry, root string, recursive bool, excludes [][]string,
+	interval time.Duration, done <-chan struct{}, emit func(event FsEvent) bool) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		current := pollSnapshot(root, recursive, excludes)
+		for _, event := range diffSnapshots(previous, current) {
+			if !emit(event) {
+				return
+			}
+		}
+
+		previous = current
 	}
+}
 
-	if !p.IsDir() {
-		return nil, errors.New("this path is not a directory")
+/*
+diffSnapshots returns the changes between two poll snapshots ordered by path.
+*/
+func diffSnapshots(previous map[string]snapshotEntry, current map[string]snapshotEntry) []FsEvent {
+	paths := make(map[string]bool)
+	for path := range previous {
+		paths[path] = true
+	}
+	for path := range current {
+		paths[path] = true
 	}
 
-	matches, err := filepath.Glob(filepath.Join(p.path, pattern))
+	// newEvent creates an event for the passed path
+	newEvent := func(path string, kind ChangeKind, isDir bool) FsEvent {
+		return FsEvent{ChangeEvent: ChangeEvent{Path: NewPath(path), Kind: kind}, IsDir: isDir}
+	}
+
+	var events []FsEvent
+	for _, path := range sortedKeys(paths) {
+		before, existed := previous[path]
+		after, exists := current[path]
+
+		switch {
+		case !existed:
+			events = append(events, newEvent(path, ChangeCreate, after.isDir))
+		case !exists:
+			events = append(events, newEvent(path, ChangeDelete, before.isDir))
+		case before.isDir != after.isDir:
+			// a replaced entry is reported as removed and created again
+			events = append(events, newEvent(path, ChangeDelete, before.isDir), newEvent(path, ChangeCreate, after.isDir))
+		case !after.isDir && (before.size != after.size || !before.modTime.Equal(after.modTime)):
+			events = append(events, newEvent(path, ChangeModify, false))
+		}
+	}
+
+	return events
+}
+
+/*
+resolveExisting returns the absolute path with all symbolic links resolved.
+If the path does not exist, the deepest existing parent is resolved
+and the remaining elements are appended.
+*/
+func resolveExisting(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	existing := absolute
+	var remaining []string
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, remaining...)...), nil
+		}
+
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return absolute, nil
+		}
+
+		remaining = append([]string{filepath.Base(existing)}, remaining...)
+		existing = parent
+	}
+}
+
+/*
+pathElements splits a path without volume name into its elements.
+*/
+func pathElements(path string) []string {
+	trimmed := strings.Trim(path, pathSeparator)
+	if trimmed == "" || trimmed == "." {
+		return nil
+	}
+
+	return strings.Split(trimmed, pathSeparator)
+}
+
+/*
+hasName returns whether the last element of the Path is a name,
+which is not the case for the root, "." and "..".
+*/
+func (p *Path) hasName() bool {
+	base := p.Base()
+	return base != "." && base != ".." && base != pathSeparator
+}
+
+/*
+splitUNC splits a UNC path like "\\server\share\dir" into its server, share
+and remaining path. Both separators are accepted. Windows device and
+extended-length paths starting with "\\?\" or "\\.\" are not considered UNC paths.
+*/
+func splitUNC(path string) (server string, share string, rest string, ok bool) {
+	normalized := strings.ReplaceAll(path, "\\", "/")
+	if !strings.HasPrefix(normalized, "//") {
+		return "", "", "", false
+	}
+
+	parts := strings.SplitN(normalized[2:], "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" || parts[0] == "?" || parts[0] == "." {
+		return "", "", "", false
+	}
+
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+
+	return parts[0], parts[1], rest, true
+}
+
+/*
+isReservedName returns whether the passed file name is reserved on Windows.
+*/
+func isReservedName(name string) bool {
+	// extensions and trailing spaces are ignored by Windows
+	stem, _, _ := strings.Cut(name, ".")
+	stem = strings.ToUpper(strings.TrimRight(stem, " "))
+
+	switch stem {
+	case "CON", "PRN", "AUX", "NUL", "CONIN$", "CONOUT$":
+		return true
+	}
+
+	if len(stem) == 4 && (strings.HasPrefix(stem, "COM") || strings.HasPrefix(stem, "LPT")) {
+		return stem[3] >= '1' && stem[3] <= '9'
+	}
+
+	// superscript digits are reserved as well
+	for _, prefix := range []string{"COM", "LPT"} {
+		if digit, found := strings.CutPrefix(stem, prefix); found {
+			return digit == "¹" || digit == "²" || digit == "³"
+		}
+	}
+
+	return false
+}
+
+/*
+isExtendedLength returns whether the passed cleaned path is in the Windows extended-length form.
+Paths like "\\?\C:\dir" are cleaned to "/?/C:/dir" on other operating systems, where a leading
+"?" element thus marks the extended-length form, because "?" is not a valid element on Windows.
+*/
+func isExtendedLength(path string) bool {
+	if runtime.GOOS != "windows" {
+		return strings.HasPrefix(path, "/?/")
+	}
+
+	return strings.HasPrefix(path, `\\?\`)
+}
+
+/*
+maxElementLength is the maximum length of a path element on common file systems,
+measured in bytes or in UTF-16 code units on Windows.
+*/
+const maxElementLength = 255
+
+/*
+elementLength returns the length of the passed path element as counted by the target
+operating system, which is the number of UTF-16 code units on Windows and bytes otherwise.
+*/
+func elementLength(element string, target OS) int {
+	if target == OSWindows {
+		return len(utf16.Encode([]rune(element)))
+	}
+
+	return len(element)
+}
+
+/*
+validateElement returns an error if the passed path element is not a valid file name on the target operating system.
+*/
+func validateElement(element string, target OS) error {
+	if strings.ContainsRune(element, 0) {
+		return fmt.Errorf("element '%s' contains a null byte", element)
+	}
+
+	if length := elementLength(element, target); length > maxElementLength {
+		return fmt.Errorf("element '%s' is %d long, but must not be longer than %d", element, length, maxElementLength)
+	}
+
+	if target != OSWindows {
+		return nil
+	}
+
+	for _, r := range element {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return fmt.Errorf("element '%s' contains the illegal character %q", element, r)
+		}
+	}
+
+	if isReservedName(element) {
+		return fmt.Errorf("element '%s' is a reserved name", element)
+	}
+
+	if strings.HasSuffix(element, ".") || strings.HasSuffix(element, " ") {
+		return fmt.Errorf("element '%s' ends with a dot or space", element)
+	}
+
+	return nil
+}
+
+/*
+isDriveLetter returns whether the passed path element is a Windows drive like "C:".
+*/
+func isDriveLetter(element string) bool {
+	return len(element) == 2 && element[1] == ':' &&
+		(element[0] >= 'a' && element[0] <= 'z' || element[0] >= 'A' && element[0] <= 'Z')
+}
+
+/*
+slugTransliterations maps lowercase Latin letters with diacritics and ligatures to ASCII for Slugify.
+*/
+var slugTransliterations = func() map[rune]string {
+	groups := map[string]string{
+		"a":  "àáâãäåāăą",
+		"c":  "çćĉċč",
+		"d":  "ďđð",
+		"e":  "èéêëēĕėęě",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįı",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņňŉ",
+		"o":  "òóôõöøōŏő",
+		"r":  "ŕŗř",
+		"s":  "śŝşš",
+		"t":  "ţťŧ",
+		"u":  "ùúûüũūŭůűų",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+		"ae": "æ",
+		"oe": "œ",
+		"ss": "ß",
+		"th": "þ",
+	}
+
+	transliterations := make(map[rune]string)
+	for replacement, letters := range groups {
+		for _, letter := range letters {
+			transliterations[letter] = replacement
+		}
+	}
+
+	return transliterations
+}()
+
+//go:generate go run gen_unicode.go
+
+/*
+normalizeString applies the passed Unicode normalization form to a string.
+*/
+func normalizeString(s string, form Normalization) string {
+	switch form {
+	case NormalizationNFC:
+		return normalizeNFC(s)
+	case NormalizationNFD:
+		return normalizeNFD(s)
+	default:
+		return s
+	}
+}
+
+// constants of the algorithmic Hangul syllable (de)composition
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+/*
+combiningClass returns the canonical combining class of a character.
+*/
+func combiningClass(r rune) uint8 {
+	i := sort.Search(len(combiningClasses), func(i int) bool {
+		return combiningClasses[i].hi >= r
+	})
+	if i == len(combiningClasses) || combiningClasses[i].lo > r {
+		return 0
+	}
+
+	return combiningClasses[i].class
+}
+
+/*
+decomposeRune appends the full canonical decomposition of a character to dst.
+*/
+func decomposeRune(dst []rune, r rune) []rune {
+	if r >= hangulSBase && r < hangulSBase+hangulSCount {
+		index := r - hangulSBase
+		dst = append(dst, hangulLBase+index/hangulNCount, hangulVBase+(index%hangulNCount)/hangulTCount)
+		if t := index % hangulTCount; t != 0 {
+			dst = append(dst, hangulTBase+t)
+		}
+
+		return dst
+	}
+
+	decomposition, ok := canonicalDecompositions[r]
+	if !ok {
+		return append(dst, r)
+	}
+
+	dst = decomposeRune(dst, decomposition[0])
+	if decomposition[1] != 0 {
+		dst = decomposeRune(dst, decomposition[1])
+	}
+
+	return dst
+}
+
+/*
+decompose returns the canonically decomposed and ordered characters of a string.
+Invalid UTF-8 bytes are replaced by utf8.RuneError.
+*/
+func decompose(s string) []rune {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		runes = decomposeRune(runes, r)
+	}
+
+	// canonical ordering: stable sort of each sequence of combining characters
+	for i := 0; i < len(runes); {
+		if combiningClass(runes[i]) == 0 {
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(runes) && combiningClass(runes[end]) != 0 {
+			end++
+		}
+
+		sequence := runes[i:end]
+		sort.SliceStable(sequence, func(a, b int) bool {
+			return combiningClass(sequence[a]) < combiningClass(sequence[b])
+		})
+		i = end
+	}
+
+	return runes
+}
+
+/*
+composePair returns the primary composite of two characters, if any.
+*/
+func composePair(first rune, second rune) (rune, bool) {
+	if first >= hangulLBase && first < hangulLBase+hangulLCount &&
+		second >= hangulVBase && second < hangulVBase+hangulVCount {
+		return hangulSBase + ((first-hangulLBase)*hangulVCount+second-hangulVBase)*hangulTCount, true
+	}
+
+	if first >= hangulSBase && first < hangulSBase+hangulSCount && (first-hangulSBase)%hangulTCount == 0 &&
+		second > hangulTBase && second < hangulTBase+hangulTCount {
+		return first + second - hangulTBase, true
+	}
+
+	composite, ok := canonicalCompositions[[2]rune{first, second}]
+	return composite, ok
+}
+
+/*
+normalizeNFD returns the Unicode normalization form D of a string.
+*/
+func normalizeNFD(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	return string(decompose(s))
+}
+
+/*
+normalizeNFC returns the Unicode normalization form C of a string.
+*/
+func normalizeNFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	runes := decompose(s)
+	starter := -1
+	var lastClass uint8
+	result := runes[:0]
+	for _, r := range runes {
+		class := combiningClass(r)
+
+		// a character combines with the last starter if it is not blocked
+		// by another character of the same or a higher combining class
+		if starter >= 0 && (lastClass < class || lastClass == 0 && len(result)-1 == starter) {
+			if composite, ok := composePair(result[starter], r); ok {
+				result[starter] = composite
+				continue
+			}
+		}
+
+		if class == 0 {
+			starter = len(result)
+		}
+
+		lastClass = class
+		result = append(result, r)
+	}
+
+	return string(result)
+}
+
+/*
+isASCII returns whether a string only consists of ASCII characters.
+*/
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+foldCase returns the full Unicode case folding of a string.
+*/
+func foldCase(s string) string {
+	if isASCII(s) {
+		return strings.ToLower(s)
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for _, r := range s {
+		if folded, ok := caseFoldings[r]; ok {
+			builder.WriteString(folded)
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+/*
+newUserDir returns the Path of a well-known directory of the current user.
+*/
+func newUserDir(dir userDir) (*Path, error) {
+	if folder, ok, err := knownFolder(dir); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return NewPath(folder), nil
+	}
+
+	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
 	}
 
-	return matches, nil
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		if folder, ok := xdgUserDir(home, userDirs[dir].xdgKey); ok {
+			return NewPath(folder), nil
+		}
+	}
+
+	return NewPath(home).JoinStrings(userDirs[dir].name), nil
+}
+
+/*
+xdgUserDir looks up a directory in the XDG user directories configuration,
+i.e. the file user-dirs.dirs inside $XDG_CONFIG_HOME or ~/.config.
+The boolean return value is false if the file or entry does not exist.
+*/
+func xdgUserDir(home string, key string) (string, bool) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(configHome) {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	content, err := os.ReadFile(filepath.Join(configHome, "user-dirs.dirs"))
+	if err != nil {
+		return "", false
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		name, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || name != key {
+			continue
+		}
+
+		// values are either absolute or relative to $HOME, e.g. "$HOME/Downloads"
+		value = strings.Trim(value, "\"")
+		if rest, ok := strings.CutPrefix(value, "$HOME"); ok {
+			value = home + rest
+		}
+
+		if !filepath.IsAbs(value) {
+			return "", false
+		}
+
+		return value, true
+	}
+
+	return "", false
+}
+
+/*
+newAppDir returns the Path of the passed application inside the directory
+returned by baseDir.
+*/
+func newAppDir(app string, baseDir func() (string, error)) (*Path, error) {
+	if app == "" || !filepath.IsLocal(app) {
+		return nil, fmt.Errorf("invalid application name %q", app)
+	}
+
+	base, err := baseDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPath(base).JoinStrings(app), nil
 }
 
-func equalsStringCaseInsensitive(first string, second string) bool {
-	// lowercase the strings and compare them
-	thisLowerCase := strings.ToLower(cleanPathString(first))
-	otherLowerCase := strings.ToLower(cleanPathString(second))
+/*
+userDataDir returns the default root directory for user-specific data files.
+*/
+func userDataDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		dir := os.Getenv("LocalAppData")
+		if dir == "" {
+			return "", errors.New("%LocalAppData% is not defined")
+		}
+
+		return dir, nil
+	case "darwin", "ios":
+		// equal to the configuration directory
+		return os.UserConfigDir()
+	}
+
+	dir := os.Getenv("XDG_DATA_HOME")
+	if filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("neither $XDG_DATA_HOME nor $HOME are defined")
+	}
+
+	return filepath.Join(home, ".local", "share"), nil
+}
 
-	// if not equal in lowercase, then they are not the same path
-	// this tests if the actual path strings are equal
-	return thisLowerCase == otherLowerCase
+/*
+formatWithVerb returns the formatting directive of the passed state with the
+passed verb, excluding the '+' and '#' flags, which have a special meaning for Paths.
+*/
+func formatWithVerb(f fmt.State, verb rune) string {
+	return strings.Map(func(r rune) rune {
+		if r == '+' || r == '#' {
+			return -1
+		}
+		return r
+	}, fmt.FormatString(f, verb))
 }
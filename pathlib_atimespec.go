@@ -0,0 +1,35 @@
+//go:build darwin || freebsd || netbsd
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+/*
+fileAccessTime returns the last access time of the passed file info.
+The boolean return value is false if the time is not available.
+*/
+func fileAccessTime(info os.FileInfo) (time.Time, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(stat.Atimespec.Unix()), true
+}
+
+/*
+fileChangeTime returns the last status change time of the passed file info.
+The boolean return value is false if the time is not available.
+*/
+func fileChangeTime(info os.FileInfo) (time.Time, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(stat.Ctimespec.Unix()), true
+}
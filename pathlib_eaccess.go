@@ -0,0 +1,26 @@
+//go:build linux
+
+package pathlib
+
+import (
+	"syscall"
+)
+
+const (
+	// atFdCwd makes faccessat resolve relative paths against the current working directory.
+	atFdCwd = -0x64
+
+	// atEAccess makes faccessat check the effective instead of the real user and group ids.
+	atEAccess = 0x200
+)
+
+/*
+hasAccess returns whether the current process may access the passed path
+with the passed access mode, a combination of accessRead, accessWrite and accessExecute.
+The effective user and group ids are checked, as when actually accessing the path.
+
+This function utilizes the faccessat system call.
+*/
+func hasAccess(path string, mode uint32) bool {
+	return syscall.Faccessat(atFdCwd, path, mode, atEAccess) == nil
+}
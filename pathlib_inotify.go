@@ -0,0 +1,249 @@
+//go:build linux && pathlib_inotify
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+/*
+inotifyMask contains the inotify events watched by watchTreeNative.
+*/
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+/*
+inotifyWatcher watches a directory tree using inotify.
+*/
+type inotifyWatcher struct {
+	file     *os.File
+	fd       int
+	root     string
+	excludes [][]string
+	dirs     map[int32]string
+}
+
+/*
+watchTreeNative returns a native watcher for the directory tree at root.
+All directories are watched before returning, so that no changes are missed.
+
+This function utilizes inotify.
+*/
+func watchTreeNative(root string, excludes [][]string) (func(done <-chan struct{}, emit func(event FsEvent) bool), bool, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// a non-blocking file is integrated into the runtime poller, which allows interrupting reads
+	watcher := &inotifyWatcher{
+		file:     os.NewFile(uintptr(fd), "inotify"),
+		fd:       fd,
+		root:     root,
+		excludes: excludes,
+		dirs:     make(map[int32]string),
+	}
+
+	_, err = watcher.addTree(root)
+	if err != nil {
+		_ = watcher.file.Close()
+		return nil, false, err
+	}
+
+	return watcher.run, true, nil
+}
+
+/*
+addTree watches dir and all directories below it and returns all entries below dir.
+*/
+func (w *inotifyWatcher) addTree(dir string) ([]FsEvent, error) {
+	var created []FsEvent
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// entries may be removed while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if path != w.root && isExcluded(w.root, path, w.excludes, false) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if path != dir {
+			created = append(created, FsEvent{ChangeEvent: ChangeEvent{Path: NewPath(path), Kind: ChangeCreate}, IsDir: d.IsDir()})
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			if err == syscall.ENOENT {
+				return nil
+			}
+
+			return err
+		}
+
+		w.dirs[int32(wd)] = path
+		return nil
+	})
+
+	return created, err
+}
+
+/*
+run reads inotify events and passes them to emit until done is closed or emit returns false.
+Read errors, overflows of the event queue and the removal of the root are passed
+to emit as errors and stop watching.
+*/
+func (w *inotifyWatcher) run(done <-chan struct{}, emit func(event FsEvent) bool) {
+	var closeOnce sync.Once
+	closeFile := func() {
+		closeOnce.Do(func() {
+			_ = w.file.Close()
+		})
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	defer closeFile()
+
+	go func() {
+		select {
+		case <-done:
+			// closing the file interrupts the pending read
+			closeFile()
+		case <-stopped:
+		}
+	}()
+
+	buffer := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		n, err := w.file.Read(buffer)
+		if err != nil {
+			// reads fail after closing the file when done is closed
+			select {
+			case <-done:
+			default:
+				emit(FsEvent{Err: err})
+			}
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			nameBytes := buffer[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			// events were dropped by the kernel, thus changes would be missed silently
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				emit(FsEvent{Err: errors.New("inotify event queue overflowed")})
+				return
+			}
+
+			dir, ok := w.dirs[event.Wd]
+			if !ok {
+				continue
+			}
+
+			// the root cannot be watched anymore once it is removed, moved or unmounted
+			if dir == w.root && event.Mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF|syscall.IN_IGNORED) != 0 {
+				emit(FsEvent{Err: fmt.Errorf("watched directory '%s' was removed or moved", w.root)})
+				return
+			}
+
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				delete(w.dirs, event.Wd)
+				continue
+			}
+
+			// changes of watched directories themselves are reported through their parents
+			if event.Mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF) != 0 {
+				continue
+			}
+
+			// names are padded with null bytes
+			name := string(nameBytes)
+			for len(name) > 0 && name[len(name)-1] == 0 {
+				name = name[:len(name)-1]
+			}
+
+			path := filepath.Join(dir, name)
+			if isExcluded(w.root, path, w.excludes, false) {
+				continue
+			}
+
+			for _, fsEvent := range w.translate(event.Mask, path) {
+				if !emit(fsEvent) {
+					return
+				}
+			}
+		}
+	}
+}
+
+/*
+translate converts an inotify event mask for path into FsEvents.
+Created directories are watched and their entries reported as created as well.
+*/
+func (w *inotifyWatcher) translate(mask uint32, path string) []FsEvent {
+	isDir := mask&syscall.IN_ISDIR != 0
+	event := FsEvent{ChangeEvent: ChangeEvent{Path: NewPath(path)}, IsDir: isDir}
+
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		event.Kind = ChangeCreate
+		if !isDir {
+			return []FsEvent{event}
+		}
+
+		// entries may have been created before the directory was watched
+		created, _ := w.addTree(path)
+		return append([]FsEvent{event}, created...)
+	case mask&(syscall.IN_DELETE|syscall.IN_MOVED_FROM) != 0:
+		event.Kind = ChangeDelete
+		if isDir && mask&syscall.IN_MOVED_FROM != 0 {
+			w.removeTree(path)
+		}
+		return []FsEvent{event}
+	case mask&syscall.IN_MODIFY != 0 && !isDir:
+		event.Kind = ChangeModify
+		return []FsEvent{event}
+	default:
+		return nil
+	}
+}
+
+/*
+removeTree stops watching dir and all directories below it,
+which is required for directories moved out of their watched location.
+*/
+func (w *inotifyWatcher) removeTree(dir string) {
+	prefix := dir + string(filepath.Separator)
+
+	for wd, path := range w.dirs {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			_, _ = syscall.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.dirs, wd)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+//go:build linux && pathlib_inotify
+
+package pathlib
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath_WatchTreeInotify(t *testing.T) {
+	for _, remove := range []bool{true, false} {
+		tempPath := NewPath(t.TempDir())
+		root := tempPath.JoinStrings("root")
+		assert.NoError(t, root.JoinStrings("dir").MkdirAll(0755))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := root.WatchTree(ctx, WatchOptions{})
+		assert.NoError(t, err)
+
+		// removing or moving the root ends the stream with an error
+		if remove {
+			assert.NoError(t, os.RemoveAll(root.String()))
+		} else {
+			assert.NoError(t, os.Rename(root.String(), tempPath.JoinStrings("moved").String()))
+		}
+
+		var last FsEvent
+		timeout := time.After(5 * time.Second)
+	receive:
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					break receive
+				}
+				last = event
+			case <-timeout:
+				t.Fatal("timed out waiting for the events to be closed")
+			}
+		}
+
+		assert.Error(t, last.Err)
+		assert.Nil(t, last.Path)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unix && !linux
+
+package pathlib
+
+import (
+	"syscall"
+)
+
+/*
+hasAccess returns whether the current process may access the passed path
+with the passed access mode, a combination of accessRead, accessWrite and accessExecute.
+The real user and group ids are checked, which differ from the effective ones
+used when actually accessing the path in setuid or setgid programs.
+
+This function utilizes the access system call.
+*/
+func hasAccess(path string, mode uint32) bool {
+	return syscall.Access(path, mode) == nil
+}
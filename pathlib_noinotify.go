@@ -0,0 +1,12 @@
+//go:build !linux || !pathlib_inotify
+
+package pathlib
+
+/*
+watchTreeNative returns a native watcher for the directory tree at root.
+No native backend is available in this build, thus false is always returned
+and WatchTree falls back to polling.
+*/
+func watchTreeNative(root string, excludes [][]string) (func(done <-chan struct{}, emit func(event FsEvent) bool), bool, error) {
+	return nil, false, nil
+}
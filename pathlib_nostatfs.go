@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !dragonfly && !openbsd && !windows
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+diskUsage returns the disk usage of the filesystem containing the passed path.
+Disk usage is not supported on this platform, thus an error is always returned.
+*/
+func diskUsage(path string) (DiskUsage, error) {
+	return DiskUsage{}, fmt.Errorf("disk usage is not available: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,24 @@
+//go:build !linux && !openbsd && !solaris && !illumos && !dragonfly && !darwin && !freebsd && !netbsd && !windows
+
+package pathlib
+
+import (
+	"os"
+	"time"
+)
+
+/*
+fileAccessTime returns the last access time of the passed file info.
+Access times are not supported on this platform, thus false is always returned.
+*/
+func fileAccessTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+/*
+fileChangeTime returns the last status change time of the passed file info.
+Change times are not supported on this platform, thus false is always returned.
+*/
+func fileChangeTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
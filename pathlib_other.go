@@ -0,0 +1,98 @@
+//go:build !unix && !windows
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+/*
+fileOwner returns the numeric user and group id of the passed file info.
+Ownership is not supported on this platform, thus false is always returned.
+*/
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
+
+/*
+isCrossDeviceError returns whether the passed error was caused by
+an operation across different filesystems.
+This cannot be detected on this platform, thus false is always returned.
+*/
+func isCrossDeviceError(err error) bool {
+	return false
+}
+
+/*
+hasAccess returns whether the current process may access the passed path
+with the passed access mode, a combination of accessRead, accessWrite and accessExecute.
+
+The permission bits are checked heuristically: access is granted
+if any of the owner, group or other classes grant it.
+*/
+func hasAccess(path string, mode uint32) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	perm := uint32(info.Mode().Perm())
+	for _, class := range []uint32{perm >> 6, perm >> 3, perm} {
+		if class&mode == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+isHidden returns whether the passed path is hidden.
+On this platform, files starting with a dot are considered hidden.
+*/
+func isHidden(path string) (bool, error) {
+	return isDotfile(path), nil
+}
+
+/*
+fileDiskSize returns the number of bytes allocated on disk for the passed file info.
+The allocation is not available on this platform, thus false is always returned.
+*/
+func fileDiskSize(info os.FileInfo) (int64, bool) {
+	return 0, false
+}
+
+/*
+fileID returns the device and inode number of the passed path.
+File identifiers are not supported on this platform, thus an error is always returned.
+*/
+func fileID(path string) (uint64, uint64, error) {
+	return 0, 0, fmt.Errorf("file identifiers are not available: %w", errors.ErrUnsupported)
+}
+
+/*
+isJunction returns whether the passed path is an NTFS junction point.
+Junctions only exist on Windows, thus false is returned for every existing path.
+*/
+func isJunction(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	return false, err
+}
+
+/*
+fileLinkCount returns the number of hard links of the passed path.
+Link counts are not supported on this platform, thus an error is always returned.
+*/
+func fileLinkCount(path string) (uint64, error) {
+	return 0, fmt.Errorf("link count is not available: %w", errors.ErrUnsupported)
+}
+
+/*
+knownFolder returns the location of a Windows known folder.
+Known folders only exist on Windows, thus false is always returned.
+*/
+func knownFolder(dir userDir) (string, bool, error) {
+	return "", false, nil
+}
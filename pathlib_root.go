@@ -0,0 +1,116 @@
+//go:build go1.24
+
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+/*
+Root is a directory jail, whose methods guarantee that the resulting
+paths and opened files never escape the base directory, neither through
+'..' components, absolute paths nor symbolic links pointing outside.
+It has to be closed after use.
+
+This type utilizes os.Root, see there for platform specific limitations.
+It is only available when building with Go 1.24 or later.
+*/
+type Root struct {
+	base *Path
+	root *os.Root
+}
+
+/*
+NewRoot opens the base directory as Root.
+
+This function utilizes os.OpenRoot.
+*/
+func NewRoot(base *Path) (*Root, error) {
+	root, err := os.OpenRoot(base.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Root{base: base.Copy(), root: root}, nil
+}
+
+/*
+Close closes the Root.
+*/
+func (r *Root) Close() error {
+	return r.root.Close()
+}
+
+/*
+Base returns a copy of the base directory.
+*/
+func (r *Root) Base() *Path {
+	return r.base.Copy()
+}
+
+/*
+Join joins the passed elements onto the base directory. An error is returned
+if the result lexically escapes the base, or if its existing part resolves
+outside of it through symbolic links. The result may not exist.
+
+Note that the returned Path is not protected against later changes of the file system,
+use Open to access files safely.
+*/
+func (r *Root) Join(elements ...string) (*Path, error) {
+	rel := filepath.Join(elements...)
+	if rel == "" {
+		rel = "."
+	}
+
+	if !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("path '%s' escapes from root", rel)
+	}
+
+	// the deepest existing part must resolve within the root
+	for existing := rel; ; existing = filepath.Dir(existing) {
+		_, err := r.root.Stat(existing)
+		if err == nil {
+			break
+		}
+
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		if existing == "." {
+			break
+		}
+	}
+
+	return r.base.JoinStrings(rel), nil
+}
+
+/*
+Open opens the named file within the root for reading.
+It is the caller's responsibility to close the file.
+
+This function utilizes os.Root.Open.
+*/
+func (r *Root) Open(name string) (*os.File, error) {
+	return r.root.Open(name)
+}
+
+/*
+Glob returns all paths within the root matching the passed pattern as in FsPath.Glob.
+Symbolic links are not followed while matching.
+*/
+func (r *Root) Glob(pattern string) ([]*Path, error) {
+	matches, err := PathOn(r.root.FS(), ".").Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]*Path, len(matches))
+	for idx, match := range matches {
+		paths[idx] = r.base.JoinStrings(filepath.FromSlash(match.path))
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,94 @@
+//go:build go1.24
+
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoot(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	base := tempPath.JoinStrings("base")
+
+	assert.NoError(t, base.JoinStrings("dir").MkdirAll(0755))
+	assert.NoError(t, os.WriteFile(base.JoinStrings("dir", "file.txt").String(), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("secret.txt").String(), []byte("secret"), 0644))
+
+	hasSymlinks := runtime.GOOS != "windows"
+	if hasSymlinks {
+		assert.NoError(t, os.Symlink("dir", base.JoinStrings("inside").String()))
+		assert.NoError(t, os.Symlink(tempPath.String(), base.JoinStrings("absolute").String()))
+		assert.NoError(t, os.Symlink("..", base.JoinStrings("relative").String()))
+	}
+
+	root, err := NewRoot(base)
+	assert.NoError(t, err)
+	defer root.Close()
+
+	assert.Equal(t, base, root.Base())
+
+	t.Run("Join", func(t *testing.T) {
+		cases := []TestCase[[]string, *Path]{
+			{Input: []string{}, Expect: base},
+			{Input: []string{"dir", "file.txt"}, Expect: base.JoinStrings("dir", "file.txt")},
+			{Input: []string{"dir", "..", "missing", "new"}, Expect: base.JoinStrings("missing", "new")},
+			{Input: []string{".."}, Error: true},
+			{Input: []string{"dir", "../..", "secret.txt"}, Error: true},
+			{Input: []string{tempPath.String()}, Error: true},
+		}
+
+		if hasSymlinks {
+			cases = append(cases,
+				TestCase[[]string, *Path]{Input: []string{"inside", "file.txt"}, Expect: base.JoinStrings("inside", "file.txt")},
+				TestCase[[]string, *Path]{Input: []string{"absolute", "secret.txt"}, Error: true},
+				TestCase[[]string, *Path]{Input: []string{"relative", "missing"}, Error: true},
+			)
+		}
+
+		for i, testCase := range cases {
+			cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+		}
+
+		runForResultsE(t, cases, func(t *testing.T, input []string, expect *Path, error bool) {
+			joined, err := root.Join(input...)
+			assert.Equal(t, error, err != nil)
+			assert.Equal(t, expect, joined)
+		})
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		file, err := root.Open(filepath.Join("dir", "file.txt"))
+		assert.NoError(t, err)
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+		assert.NoError(t, file.Close())
+
+		_, err = root.Open(filepath.Join("..", "secret.txt"))
+		assert.Error(t, err)
+
+		if hasSymlinks {
+			_, err = root.Open(filepath.Join("absolute", "secret.txt"))
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Glob", func(t *testing.T) {
+		matches, err := root.Glob("**/*.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, []*Path{base.JoinStrings("dir", "file.txt")}, matches)
+
+		_, err = root.Glob("[z")
+		assert.Error(t, err)
+	})
+
+	_, err = NewRoot(tempPath.JoinStrings("missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package pathlib
+
+import (
+	"syscall"
+)
+
+/*
+diskUsage returns the disk usage of the filesystem containing the passed path.
+
+This function utilizes the statfs system call.
+Linux reports the block counts in units of the fragment size, which may differ from the block size.
+*/
+func diskUsage(path string) (DiskUsage, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return DiskUsage{}, err
+	}
+
+	blockSize := uint64(stat.Frsize)
+	total := uint64(stat.Blocks) * blockSize
+
+	return DiskUsage{
+		Total:     total,
+		Used:      total - uint64(stat.Bfree)*blockSize,
+		Available: uint64(stat.Bavail) * blockSize,
+	}, nil
+}
@@ -0,0 +1,29 @@
+//go:build openbsd
+
+package pathlib
+
+import (
+	"syscall"
+)
+
+/*
+diskUsage returns the disk usage of the filesystem containing the passed path.
+
+This function utilizes the statfs system call.
+*/
+func diskUsage(path string) (DiskUsage, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return DiskUsage{}, err
+	}
+
+	blockSize := uint64(stat.F_bsize)
+	total := uint64(stat.F_blocks) * blockSize
+
+	return DiskUsage{
+		Total:     total,
+		Used:      total - uint64(stat.F_bfree)*blockSize,
+		Available: uint64(stat.F_bavail) * blockSize,
+	}, nil
+}
@@ -1,13 +1,36 @@
 package pathlib
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"embed"
+	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
 	"os"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 type TestInput[I any] struct {
@@ -102,6 +125,19 @@ func TestPathConversions(t *testing.T) {
 			assert.Equal(t, expect.String(), string(marshaled))
 		})
 
+		t.Run("binary marshalling", func(t *testing.T) {
+			marshaled, err := inputPath.MarshalBinary()
+			assert.NoError(t, err)
+
+			text, err := inputPath.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, text, marshaled)
+
+			var unmarshaled Path
+			assert.NoError(t, unmarshaled.UnmarshalBinary(marshaled))
+			assert.Equal(t, *expect, unmarshaled)
+		})
+
 		t.Run("json unmarshalling", func(t *testing.T) {
 			var emptyPaths []*Path
 			input := fmt.Sprintf(`["%s"]`, inputPath.String())
@@ -119,6 +155,205 @@ func TestPathConversions(t *testing.T) {
 
 			assert.Equal(t, fmt.Sprintf(`["%s"]`, expect.String()), string(marshaled))
 		})
+
+		t.Run("gob round trip", func(t *testing.T) {
+			type gobStruct struct {
+				Pointer *Path
+				Value   Path
+				Slice   []*Path
+			}
+
+			var buffer bytes.Buffer
+			err := gob.NewEncoder(&buffer).Encode(gobStruct{Pointer: inputPath, Value: *inputPath, Slice: []*Path{inputPath}})
+			assert.NoError(t, err)
+
+			var decoded gobStruct
+			err = gob.NewDecoder(&buffer).Decode(&decoded)
+			assert.NoError(t, err)
+
+			assert.Equal(t, expect, decoded.Pointer)
+			assert.Equal(t, *expect, decoded.Value)
+			assert.Equal(t, []*Path{expect}, decoded.Slice)
+		})
+	})
+}
+
+func TestPathValue(t *testing.T) {
+	t.Run("map keys", func(t *testing.T) {
+		seen := map[PathValue]int{}
+		for _, input := range []string{"foo/bar", "./foo/bar/", "foo/../foo/bar", "foo/baz"} {
+			seen[NewPath(input).Value()]++
+		}
+
+		assert.Equal(t, map[PathValue]int{NewPathValue("foo/bar"): 3, NewPathValue("foo/baz"): 1}, seen)
+		assert.NotEqual(t, NewPath("foo/bar"), NewPath("foo/bar").Value())
+		assert.True(t, NewPath("foo/bar") != NewPath("foo/bar"))
+		assert.True(t, NewPath("foo/bar").Value() == NewPathValue("./foo//bar"))
+	})
+
+	t.Run("conversion", func(t *testing.T) {
+		value := NewPathValue("foo/../bar/")
+
+		assert.Equal(t, NewPath("bar"), value.Path())
+		assert.Equal(t, NewPath("bar").String(), value.String())
+		assert.Equal(t, value, value.Path().Value())
+		assert.Equal(t, PathValue{}, new(PathValue).Path().Value())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		type config struct {
+			Root  PathValue
+			Sizes map[PathValue]int
+		}
+
+		input := config{Root: NewPathValue("/srv"), Sizes: map[PathValue]int{NewPathValue("a"): 1}}
+		marshaled, err := json.Marshal(input)
+		assert.NoError(t, err)
+
+		expect, err := json.Marshal(map[string]any{"Root": NewPath("/srv").Raw(), "Sizes": map[string]int{"a": 1}})
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(expect), string(marshaled))
+
+		var decoded config
+		assert.NoError(t, json.Unmarshal([]byte(`{"Root": "/srv/./", "Sizes": {"./a": 1}}`), &decoded))
+		assert.Equal(t, input, decoded)
+	})
+}
+
+func TestPath_Set(t *testing.T) {
+	cases := []TestCase[string, *Path]{
+		{Input: "foo/../bar/", Expect: NewPath("bar")},
+		{Input: " /foo/bar ", Expect: NewPath("/foo/bar")},
+		{Input: "", Error: true},
+		{Input: "foo\x00bar", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect *Path, error bool) {
+		path := NewPath("unchanged")
+		err := path.Set(input)
+		assert.Equal(t, error, err != nil)
+
+		if error {
+			assert.Equal(t, NewPath("unchanged"), path)
+		} else {
+			assert.Equal(t, expect, path)
+		}
+	})
+}
+
+func TestPathFlag(t *testing.T) {
+	var _ flag.Value = new(Path)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var config Path
+	PathVar(fs, &config, "config", "conf/../app.toml", "configuration file")
+	output := PathFlag(fs, "output", "", "output directory")
+
+	assert.Equal(t, NewPath("app.toml"), &config)
+	assert.Equal(t, NewPath(""), output)
+	assert.Equal(t, NewPath("").String(), fs.Lookup("output").DefValue)
+	assert.Equal(t, "app.toml", fs.Lookup("config").DefValue)
+
+	assert.NoError(t, fs.Parse([]string{"-config", "/etc/app/./app.toml", "-output", "out/"}))
+	assert.Equal(t, NewPath("/etc/app/app.toml"), &config)
+	assert.Equal(t, NewPath("out"), output)
+
+	assert.Error(t, fs.Parse([]string{"-output", ""}))
+}
+
+func TestPath_XML(t *testing.T) {
+	type compile struct {
+		Include Path    `xml:"Include,attr"`
+		Link    *Path   `xml:"Link,attr,omitempty"`
+		Output  Path    `xml:"Output"`
+		Inputs  []*Path `xml:"Input"`
+	}
+
+	t.Run("marshal", func(t *testing.T) {
+		value := compile{
+			Include: *NewPath("src/main.cs"),
+			Output:  *NewPath("bin/out.dll"),
+			Inputs:  []*Path{NewPath("a.cs"), NewPath("b.cs")},
+		}
+
+		marshaled, err := xml.Marshal(&value)
+		assert.NoError(t, err)
+
+		expect := fmt.Sprintf(`<compile Include="%s"><Output>%s</Output><Input>a.cs</Input><Input>b.cs</Input></compile>`,
+			NewPath("src/main.cs").Raw(), NewPath("bin/out.dll").Raw())
+		assert.Equal(t, expect, string(marshaled))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// nil Paths are omitted without the omitempty option
+		value := struct {
+			XMLName xml.Name `xml:"compile"`
+			Link    *Path    `xml:"Link,attr"`
+			Output  *Path    `xml:"Output"`
+		}{}
+
+		marshaled, err := xml.Marshal(value)
+		assert.NoError(t, err)
+		assert.Equal(t, `<compile></compile>`, string(marshaled))
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		input := `<compile Include=" ./src/../main.cs" Link="linked/"><Output>bin/./out.dll</Output><Input>a.cs</Input><Input>lib/../b.cs</Input></compile>`
+
+		var decoded compile
+		assert.NoError(t, xml.Unmarshal([]byte(input), &decoded))
+
+		assert.Equal(t, *NewPath("main.cs"), decoded.Include)
+		assert.Equal(t, NewPath("linked"), decoded.Link)
+		assert.Equal(t, *NewPath("bin/out.dll"), decoded.Output)
+		assert.Equal(t, []*Path{NewPath("a.cs"), NewPath("b.cs")}, decoded.Inputs)
+	})
+}
+
+func TestPath_Format(t *testing.T) {
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	path := NewPath(filepath.Join("foo", "with whitespace"))
+	absolute := filepath.Join(cwd, "foo", "with whitespace")
+
+	cases := []TestCase[string, string]{
+		{Input: "%s", Expect: path.Raw()},
+		{Input: "%v", Expect: path.Raw()},
+		{Input: "%q", Expect: strconv.Quote(path.Raw())},
+		{Input: "%+v", Expect: absolute},
+		{Input: "%#v", Expect: fmt.Sprintf("pathlib.NewPath(%q)", path.Raw())},
+		{Input: "%-22s|", Expect: path.Raw() + "   |"},
+		{Input: "%.3s", Expect: "foo"},
+		{Input: "%x", Expect: hex.EncodeToString([]byte(path.Raw()))},
+		{Input: "%X", Expect: strings.ToUpper(hex.EncodeToString([]byte(path.Raw())))},
+		{Input: "%d", Expect: "%!d(string=" + path.Raw() + ")"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect string) {
+		assert.Equal(t, expect, fmt.Sprintf(input, path))
+	})
+
+	t.Run("escaped", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("whitespaces are never escaped on windows")
+		}
+
+		SetWhitespaceEscaping(true)
+		defer SetWhitespaceEscaping(false)
+
+		assert.Equal(t, `foo/with\ whitespace`, fmt.Sprintf("%s", path))
+		assert.Equal(t, `"foo/with whitespace"`, fmt.Sprintf("%q", path))
 	})
 }
 
@@ -144,7 +379,20 @@ func TestPathWhiteSpaceRepresentation(t *testing.T) {
 		})
 
 		t.Run("toString", func(t *testing.T) {
+			assert.Equal(t, internalRepr, inputPath.String())
+			assert.Equal(t, internalRepr, inputPath.Raw())
+		})
+
+		t.Run("toEscapedString", func(t *testing.T) {
+			SetWhitespaceEscaping(true)
+			defer SetWhitespaceEscaping(false)
+
 			assert.Equal(t, stringRepr, inputPath.String())
+			assert.Equal(t, internalRepr, inputPath.Raw())
+
+			marshaled, err := inputPath.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, internalRepr, string(marshaled))
 		})
 	})
 }
@@ -174,6 +422,130 @@ func TestNewHome(t *testing.T) {
 	assert.Equal(t, localHomePath, pathlibHomePath)
 }
 
+func TestNewTempRoot(t *testing.T) {
+	assert.Equal(t, NewPath(os.TempDir()), NewTempRoot())
+}
+
+func TestNewExecutable(t *testing.T) {
+	executable, err := os.Executable()
+	assert.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(executable)
+	assert.NoError(t, err)
+
+	pathlibExecutable, err := NewExecutable()
+	assert.NoError(t, err)
+	assert.Equal(t, NewPath(resolved), pathlibExecutable)
+	assert.True(t, pathlibExecutable.IsFile())
+
+	pathlibExecutableDir, err := NewExecutableDir()
+	assert.NoError(t, err)
+	assert.Equal(t, NewPath(filepath.Dir(resolved)), pathlibExecutableDir)
+	assert.True(t, pathlibExecutableDir.IsDir())
+}
+
+func TestNewUserDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		t.Skip("XDG user directories are not used on " + runtime.GOOS)
+	}
+
+	home := t.TempDir()
+	configHome := filepath.Join(home, "config")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	assert.NoError(t, os.MkdirAll(configHome, 0777))
+	assert.NoError(t, os.WriteFile(filepath.Join(configHome, "user-dirs.dirs"), []byte(
+		"# written by xdg-user-dirs-update\n"+
+			"XDG_DESKTOP_DIR=\"$HOME/Schreibtisch\"\n"+
+			"XDG_DOWNLOAD_DIR=\"/srv/downloads\"\n"+
+			"XDG_DOCUMENTS_DIR=\"relative\"\n",
+	), 0666))
+
+	cases := []TestCase[func() (*Path, error), *Path]{
+		{Name: "desktop", Input: NewDesktopDir, Expect: NewPath(home).JoinStrings("Schreibtisch")},
+		{Name: "downloads", Input: NewDownloadsDir, Expect: NewPath("/srv/downloads")},
+		{Name: "documents", Input: NewDocumentsDir, Expect: NewPath(home).JoinStrings("Documents")},
+	}
+
+	runForResults(t, cases, func(t *testing.T, input func() (*Path, error), expect *Path) {
+		dir, err := input()
+
+		assert.NoError(t, err)
+		assert.Equal(t, expect, dir)
+	})
+
+	t.Run("without configuration", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(configHome, "user-dirs.dirs")))
+
+		dir, err := NewDownloadsDir()
+		assert.NoError(t, err)
+		assert.Equal(t, NewPath(home).JoinStrings("Downloads"), dir)
+	})
+}
+
+func TestNewAppDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "ios" || runtime.GOOS == "plan9" {
+		t.Skip("XDG base directories are not used on " + runtime.GOOS)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	cases := []TestCase[func(string) (*Path, error), *Path]{
+		{Name: "config", Input: NewAppConfigDir, Expect: NewPath("/xdg/config/myapp")},
+		{Name: "cache", Input: NewAppCacheDir, Expect: NewPath("/xdg/cache/myapp")},
+		{Name: "data", Input: NewAppDataDir, Expect: NewPath(home).JoinStrings(".local", "share", "myapp")},
+	}
+
+	runForResults(t, cases, func(t *testing.T, input func(string) (*Path, error), expect *Path) {
+		dir, err := input("myapp")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expect, dir)
+
+		for _, app := range []string{"", "../myapp", "/myapp"} {
+			_, err = input(app)
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestPath_ExpandUser(t *testing.T) {
+	home, err := NewHome()
+	assert.NoError(t, err)
+
+	cases := []TestCase[string, *Path]{
+		{Input: "~", Expect: home},
+		{Input: "~/config", Expect: home.JoinStrings("config")},
+		{Input: "~/.config/app", Expect: home.JoinStrings(".config", "app")},
+		{Input: "foo/~", Expect: NewPath("foo/~")},
+		{Input: "/foo/bar", Expect: NewPath("/foo/bar")},
+	}
+
+	if current, err := user.Current(); err == nil && current.HomeDir != "" && runtime.GOOS != "windows" {
+		cases = append(cases,
+			TestCase[string, *Path]{Input: "~" + current.Username + "/config", Expect: NewPath(current.HomeDir).JoinStrings("config")},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect *Path) {
+		expanded, err := NewPathExpandUser(input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expect, expanded)
+	})
+
+	_, err = NewPathExpandUser("~pathlib-no-such-user/config")
+	assert.Error(t, err)
+}
+
 func TestPathFromParts(t *testing.T) {
 	cases := []TestCase[[]string, *Path]{
 		{Input: []string{"."}, Expect: NewPath(".")},
@@ -192,6 +564,186 @@ func TestPathFromParts(t *testing.T) {
 	})
 }
 
+func TestReadFirstExisting(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	for _, name := range []string{"first", "second"} {
+		err := os.WriteFile(tempPath.JoinStrings(name).String(), []byte(name), 0644)
+		assert.NoError(t, err)
+	}
+
+	err := os.Mkdir(tempPath.JoinStrings("dir").String(), 0755)
+	assert.NoError(t, err)
+
+	cases := []TestCase[[]string, string]{
+		{Input: []string{"first", "second"}, Expect: "first"},
+		{Input: []string{"second", "first"}, Expect: "second"},
+		{Input: []string{"missing", "second"}, Expect: "second"},
+		{Input: []string{"dir", "missing", "first"}, Expect: "first"},
+		{Input: []string{"missing", "dir"}, Error: true},
+		{Input: []string{}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []string, expect string, error bool) {
+		candidates := make([]*Path, len(input))
+		for i, name := range input {
+			candidates[i] = tempPath.JoinStrings(name)
+		}
+
+		content, path, err := ReadFirstExisting(candidates...)
+		assert.Equal(t, error, err != nil)
+
+		if error {
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		} else {
+			assert.Equal(t, expect, string(content))
+			assert.Equal(t, tempPath.JoinStrings(expect), path)
+		}
+	})
+}
+
+func TestFirstExisting(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	err := os.WriteFile(tempPath.JoinStrings("file").String(), nil, 0644)
+	assert.NoError(t, err)
+
+	err = os.Mkdir(tempPath.JoinStrings("dir").String(), 0755)
+	assert.NoError(t, err)
+
+	// the expected slice holds the results of FirstExisting, FirstFile and FirstDir
+	cases := []TestCase[[]string, []string]{
+		{Input: []string{"file", "dir"}, Expect: []string{"file", "file", "dir"}},
+		{Input: []string{"dir", "file"}, Expect: []string{"dir", "file", "dir"}},
+		{Input: []string{"missing", "dir"}, Expect: []string{"dir", "", "dir"}},
+		{Input: []string{"missing", "file"}, Expect: []string{"file", "file", ""}},
+		{Input: []string{"missing"}, Expect: []string{"", "", ""}},
+		{Input: []string{}, Expect: []string{"", "", ""}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect []string) {
+		assert.Len(t, expect, 3)
+
+		candidates := make([]*Path, len(input))
+		for i, name := range input {
+			candidates[i] = tempPath.JoinStrings(name)
+		}
+
+		selectors := []func(...*Path) (*Path, error){FirstExisting, FirstFile, FirstDir}
+		for i, selector := range selectors {
+			path, err := selector(candidates...)
+
+			if expect[i] == "" {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				assert.Nil(t, path)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tempPath.JoinStrings(expect[i]), path)
+			}
+		}
+	})
+}
+
+func TestExpandTemplate(t *testing.T) {
+	now := time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC)
+	vars := map[string]string{"name": "app", "date": "overridden"}
+
+	hostname, err := os.Hostname()
+	assert.NoError(t, err)
+
+	cases := []TestCase[string, string]{
+		{Input: "", Expect: ""},
+		{Input: "logs/app.log", Expect: "logs/app.log"},
+		{Input: "logs/{name}.log", Expect: "logs/app.log"},
+		{Input: "logs/{date}", Expect: "logs/overridden"},
+		{Input: "logs/{time}", Expect: "logs/14-05-07"},
+		{Input: "logs/{date:2006/01}/{time:15}", Expect: "logs/2024/03/14"},
+		{Input: "logs/{unix}", Expect: fmt.Sprintf("logs/%d", now.Unix())},
+		{Input: "logs/{pid}", Expect: fmt.Sprintf("logs/%d", os.Getpid())},
+		{Input: "logs/{host}/{hostname}", Expect: fmt.Sprintf("logs/%s/%s", hostname, hostname)},
+		{Input: "logs/{{name}}", Expect: "logs/{name}"},
+		{Input: "logs/{unknown}", Error: true},
+		{Input: "logs/{name", Error: true},
+		{Input: "logs/name}", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect string, error bool) {
+		expanded, err := expandTemplate(input, vars, now)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, expanded)
+		}
+	})
+
+	t.Run("uuid", func(t *testing.T) {
+		path, err := ExpandTemplate("{uuid}", nil)
+		assert.NoError(t, err)
+		assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", path.String())
+	})
+
+	t.Run("cleaned", func(t *testing.T) {
+		path, err := ExpandTemplate("./logs/{name}/", vars)
+		assert.NoError(t, err)
+		assert.Equal(t, NewPath("logs/app"), path)
+	})
+}
+
+func TestGroupingAndPartitioning(t *testing.T) {
+	paths := []*Path{
+		NewPath("foo/a.go"),
+		NewPath("foo/b.txt"),
+		NewPath("bar/c.go"),
+		NewPath("d"),
+		NewPath("foo/with space.go"),
+	}
+
+	t.Run("GroupByParent", func(t *testing.T) {
+		groups := GroupByParent(paths)
+		assert.Equal(t, map[string][]*Path{
+			"foo": {paths[0], paths[1], paths[4]},
+			"bar": {paths[2]},
+			".":   {paths[3]},
+		}, groups)
+	})
+
+	t.Run("GroupByExtension", func(t *testing.T) {
+		groups := GroupByExtension(paths)
+		assert.Equal(t, map[string][]*Path{
+			".go":  {paths[0], paths[2], paths[4]},
+			".txt": {paths[1]},
+			"":     {paths[3]},
+		}, groups)
+	})
+
+	t.Run("Partition", func(t *testing.T) {
+		matching, other := Partition(paths, func(p *Path) bool {
+			return p.Extension() == ".go"
+		})
+		assert.Equal(t, []*Path{paths[0], paths[2], paths[4]}, matching)
+		assert.Equal(t, []*Path{paths[1], paths[3]}, other)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, GroupByParent(nil))
+		matching, other := Partition(nil, func(p *Path) bool { return true })
+		assert.Empty(t, matching)
+		assert.Empty(t, other)
+	})
+}
+
 func TestPathExistings(t *testing.T) {
 	tempDirStr := t.TempDir()
 
@@ -232,240 +784,344 @@ func TestPathExistings(t *testing.T) {
 	})
 }
 
-func TestPath_Parent(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: "."},
-		{Input: NewPath(".."), Expect: "."},
-		{Input: NewPath("/"), Expect: "/"},
-		{Input: NewPath("foo/bar"), Expect: "foo"},
-		{Input: NewPath("foo/bar.js"), Expect: "foo"},
-		{Input: NewPath("/foo/bar.js"), Expect: "/foo"},
-		{Input: NewPath("bar.js"), Expect: "."},
-		{Input: NewPath("../bar.js"), Expect: ".."},
+func TestPath_LExists(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("file").String(), nil, 0644))
+	assert.NoError(t, os.Symlink("file", tempPath.JoinStrings("link").String()))
+	assert.NoError(t, os.Symlink("missing", tempPath.JoinStrings("broken").String()))
+
+	// the expected slice holds the results of Exists, LExists and IsSymlink
+	cases := []TestCase[string, []bool]{
+		{Input: "", Expect: []bool{true, true, false}},
+		{Input: "file", Expect: []bool{true, true, false}},
+		{Input: "link", Expect: []bool{true, true, true}},
+		{Input: "broken", Expect: []bool{false, true, true}},
+		{Input: "missing", Expect: []bool{false, false, false}},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.Parent().path)
+	runForResults(t, cases, func(t *testing.T, input string, expect []bool) {
+		assert.Len(t, expect, 3)
+		path := tempPath.JoinStrings(input)
+
+		assert.Equal(t, expect[0], path.Exists())
+		assert.Equal(t, expect[1], path.LExists())
+		assert.Equal(t, expect[2], path.IsSymlink())
 	})
 }
 
-func TestPath_Parts(t *testing.T) {
-	cases := []TestCase[*Path, []string]{
-		{Input: NewPath("."), Expect: []string{"."}},
-		{Input: NewPath(".."), Expect: []string{".."}},
-		{Input: NewPath("/"), Expect: []string{}},
-		{Input: NewPath("foo/bar"), Expect: []string{"foo", "bar"}},
-		{Input: NewPath("foo/bar.js"), Expect: []string{"foo", "bar.js"}},
-		{Input: NewPath("/foo/bar.js"), Expect: []string{"foo", "bar.js"}},
-		{Input: NewPath("bar.js"), Expect: []string{"bar.js"}},
-		{Input: NewPath("../bar.js"), Expect: []string{"..", "bar.js"}},
-	}
+func TestPath_Stat(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	modTime := time.Unix(1000, 0)
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
-	}
+	filePath := tempPath.JoinStrings("file")
+	assert.NoError(t, os.WriteFile(filePath.String(), []byte("content"), 0640))
+	assert.NoError(t, os.Chmod(filePath.String(), 0640))
+	assert.NoError(t, os.Chtimes(filePath.String(), modTime, modTime))
+	assert.NoError(t, os.Symlink("file", tempPath.JoinStrings("link").String()))
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
-		assert.Equal(t, expect, input.Parts())
+	t.Run("file", func(t *testing.T) {
+		info, err := filePath.Stat()
+		assert.NoError(t, err)
+
+		assert.Equal(t, "file", info.Name)
+		assert.Equal(t, int64(7), info.Size)
+		assert.Equal(t, os.FileMode(0640), info.Mode)
+		assert.True(t, info.ModTime.Equal(modTime))
+		assert.True(t, info.IsRegular())
+		assert.False(t, info.IsDir())
+
+		if runtime.GOOS != "windows" {
+			assert.Equal(t, os.Getuid(), info.Uid)
+		}
 	})
-}
 
-func TestPath_Base(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: "."},
-		{Input: NewPath(".."), Expect: ".."},
-		{Input: NewPath("/"), Expect: "/"},
-		{Input: NewPath("foo/bar"), Expect: "bar"},
-		{Input: NewPath("foo/bar.js"), Expect: "bar.js"},
-		{Input: NewPath("/foo/bar.js"), Expect: "bar.js"},
-		{Input: NewPath("bar.js"), Expect: "bar.js"},
-		{Input: NewPath("../bar.js"), Expect: "bar.js"},
-	}
+	t.Run("symlink", func(t *testing.T) {
+		info, err := tempPath.JoinStrings("link").Stat()
+		assert.NoError(t, err)
+		assert.Equal(t, "link", info.Name)
+		assert.True(t, info.IsRegular())
+	})
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
-	}
+	t.Run("lstat", func(t *testing.T) {
+		info, err := tempPath.JoinStrings("link").Lstat()
+		assert.NoError(t, err)
+		assert.Equal(t, "link", info.Name)
+		assert.False(t, info.IsRegular())
+		assert.NotZero(t, info.Mode&os.ModeSymlink)
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.Base())
+		info, err = filePath.Lstat()
+		assert.NoError(t, err)
+		assert.True(t, info.IsRegular())
+
+		assert.NoError(t, os.Symlink("missing", tempPath.JoinStrings("broken").String()))
+		_, err = tempPath.JoinStrings("broken").Lstat()
+		assert.NoError(t, err)
+		_, err = tempPath.JoinStrings("broken").Stat()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		info, err := tempPath.Stat()
+		assert.NoError(t, err)
+		assert.True(t, info.IsDir())
+		assert.False(t, info.IsRegular())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := tempPath.JoinStrings("missing").Stat()
+		assert.ErrorIs(t, err, os.ErrNotExist)
 	})
 }
 
-func TestPath_Split(t *testing.T) {
-	cases := []TestCase[*Path, []string]{
-		{Input: NewPath("."), Expect: []string{".", "."}},
-		{Input: NewPath(".."), Expect: []string{".", ".."}},
-		{Input: NewPath("/"), Expect: []string{"/", ""}},
-		{Input: NewPath("foo/bar"), Expect: []string{"foo", "bar"}},
-		{Input: NewPath("foo/bar.js"), Expect: []string{"foo", "bar.js"}},
-		{Input: NewPath("/foo/bar.js"), Expect: []string{"/foo", "bar.js"}},
-		{Input: NewPath("bar.js"), Expect: []string{".", "bar.js"}},
-		{Input: NewPath("../bar.js"), Expect: []string{"..", "bar.js"}},
+func TestPath_Size(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("file").String(), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("empty").String(), nil, 0644))
+	assert.NoError(t, os.Symlink("file", tempPath.JoinStrings("link").String()))
+
+	cases := []TestCase[string, int64]{
+		{Input: "file", Expect: 7},
+		{Input: "empty", Expect: 0},
+		{Input: "link", Expect: 7},
+		{Input: "", Error: true},
+		{Input: "missing", Error: true},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
-		assert.Equal(t, len(expect), 2)
-
-		inputPartParent, inputPartBase := input.Split()
+	runForResultsE(t, cases, func(t *testing.T, input string, expect int64, error bool) {
+		size, err := tempPath.JoinStrings(input).Size()
+		assert.Equal(t, error, err != nil)
 
-		assert.Equal(t, expect[0], inputPartParent.path, "Parent")
-		assert.Equal(t, expect[1], inputPartBase, "Base")
+		if !error {
+			assert.Equal(t, expect, size)
+		}
 	})
 }
 
-func TestPath_Extension(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: ""},
-		{Input: NewPath(".."), Expect: ""},
-		{Input: NewPath("/"), Expect: ""},
-		{Input: NewPath("foo/bar"), Expect: ""},
-		{Input: NewPath("foo/bar.js"), Expect: ".js"},
-		{Input: NewPath("/foo/bar.js"), Expect: ".js"},
-		{Input: NewPath("bar.js"), Expect: ".js"},
-		{Input: NewPath("../bar.js"), Expect: ".js"},
-		{Input: NewPath("../bar.js.foo"), Expect: ".foo"},
-		{Input: NewPath(".bar.js"), Expect: ".js"},
-		{Input: NewPath("..bar.js"), Expect: ".js"},
-		{Input: NewPath("..bar"), Expect: ""},
-		{Input: NewPath("...bar"), Expect: ""},
-	}
+func TestPath_Times(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	accessTime := time.Unix(1000, 0)
+	modTime := time.Unix(2000, 0)
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
-	}
+	filePath := tempPath.JoinStrings("file")
+	assert.NoError(t, os.WriteFile(filePath.String(), nil, 0644))
+	assert.NoError(t, os.Chtimes(filePath.String(), accessTime, modTime))
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.Extension())
+	t.Run("ModTime", func(t *testing.T) {
+		fileModTime, err := filePath.ModTime()
+		assert.NoError(t, err)
+		assert.True(t, fileModTime.Equal(modTime))
 	})
-}
 
-func TestPath_Extensions(t *testing.T) {
-	cases := []TestCase[*Path, []string]{
-		{Input: NewPath("."), Expect: []string{}},
-		{Input: NewPath(".."), Expect: []string{}},
-		{Input: NewPath("/"), Expect: []string{}},
-		{Input: NewPath("foo/bar"), Expect: []string{}},
-		{Input: NewPath("foo/bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("/foo/bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("../bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("../bar.js.foo"), Expect: []string{".js", ".foo"}},
-		{Input: NewPath(".bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("..bar.js"), Expect: []string{".js"}},
-		{Input: NewPath("..bar"), Expect: []string{}},
-		{Input: NewPath("...bar"), Expect: []string{}},
-	}
+	t.Run("AccessTime", func(t *testing.T) {
+		fileAccessTime, err := filePath.AccessTime()
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("access time is not supported on this platform")
+		}
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
-	}
+		assert.NoError(t, err)
+		assert.True(t, fileAccessTime.Equal(accessTime))
+	})
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
-		assert.Equal(t, expect, input.Extensions())
+	t.Run("ChangeTime", func(t *testing.T) {
+		fileChangeTime, err := filePath.ChangeTime()
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("change time is not supported on this platform")
+		}
+
+		// the status change caused by Chtimes is recent
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), fileChangeTime, time.Minute)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		missingPath := tempPath.JoinStrings("missing")
+
+		_, err := missingPath.ModTime()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		_, err = missingPath.AccessTime()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		_, err = missingPath.ChangeTime()
+		assert.ErrorIs(t, err, os.ErrNotExist)
 	})
 }
 
-func TestPath_Stem(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: ""},
-		{Input: NewPath(".."), Expect: ".."},
-		{Input: NewPath("/"), Expect: ""},
-		{Input: NewPath("foo/bar"), Expect: "bar"},
-		{Input: NewPath("foo/bar.js"), Expect: "bar"},
-		{Input: NewPath("/foo/bar.js"), Expect: "bar"},
-		{Input: NewPath("bar.js"), Expect: "bar"},
-		{Input: NewPath("../bar.js"), Expect: "bar"},
-		{Input: NewPath("../bar.js.foo"), Expect: "bar.js"},
-		{Input: NewPath(".bar.js"), Expect: ".bar"},
-		{Input: NewPath("..bar.js"), Expect: "..bar"},
-		{Input: NewPath("..bar"), Expect: "..bar"},
-		{Input: NewPath("...bar"), Expect: "...bar"},
+func TestPath_Chmod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on windows")
 	}
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, tempPath.JoinStrings("tree", "nested").MkdirAll(0755))
+	for _, file := range []string{"file", "tree/file", "tree/nested/file"} {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(file).String(), nil, 0644))
 	}
+	assert.NoError(t, os.Symlink("../file", tempPath.JoinStrings("tree", "link").String()))
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.Stem())
+	// mode returns the permission bits of the passed path
+	mode := func(t *testing.T, name string) os.FileMode {
+		info, err := tempPath.JoinStrings(name).Stat()
+		assert.NoError(t, err)
+		return info.Mode.Perm()
+	}
+
+	t.Run("Chmod", func(t *testing.T) {
+		assert.NoError(t, tempPath.JoinStrings("file").Chmod(0600))
+		assert.Equal(t, os.FileMode(0600), mode(t, "file"))
+
+		assert.Error(t, tempPath.JoinStrings("missing").Chmod(0600))
+	})
+
+	t.Run("ChmodRecursive", func(t *testing.T) {
+		assert.NoError(t, tempPath.JoinStrings("tree").ChmodRecursive(0640, 0750))
+
+		assert.Equal(t, os.FileMode(0750), mode(t, "tree"))
+		assert.Equal(t, os.FileMode(0750), mode(t, "tree/nested"))
+		assert.Equal(t, os.FileMode(0640), mode(t, "tree/file"))
+		assert.Equal(t, os.FileMode(0640), mode(t, "tree/nested/file"))
+
+		// the symlink target outside the tree is not changed
+		assert.Equal(t, os.FileMode(0600), mode(t, "file"))
+
+		assert.Error(t, tempPath.JoinStrings("missing").ChmodRecursive(0640, 0750))
 	})
 }
 
-func TestPath_MinimalStem(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: ""},
-		{Input: NewPath(".."), Expect: ".."},
-		{Input: NewPath("/"), Expect: ""},
-		{Input: NewPath("foo/bar"), Expect: "bar"},
-		{Input: NewPath("foo/bar.js"), Expect: "bar"},
-		{Input: NewPath("/foo/bar.js"), Expect: "bar"},
-		{Input: NewPath("bar.js"), Expect: "bar"},
-		{Input: NewPath("../bar.js"), Expect: "bar"},
-		{Input: NewPath("../bar.js.foo"), Expect: "bar"},
-		{Input: NewPath(".bar.js"), Expect: ".bar"},
-		{Input: NewPath("..bar.js"), Expect: "..bar"},
-		{Input: NewPath("..bar"), Expect: "..bar"},
+func TestPath_Chtimes(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("file")
+	assert.NoError(t, os.WriteFile(filePath.String(), nil, 0644))
+
+	accessTime := time.Unix(1000, 0)
+	modTime := time.Unix(2000, 0)
+
+	assert.NoError(t, filePath.Chtimes(accessTime, modTime))
+
+	fileModTime, err := filePath.ModTime()
+	assert.NoError(t, err)
+	assert.True(t, fileModTime.Equal(modTime))
+
+	fileAccessTime, err := filePath.AccessTime()
+	if !errors.Is(err, errors.ErrUnsupported) {
+		assert.NoError(t, err)
+		assert.True(t, fileAccessTime.Equal(accessTime))
 	}
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	// zero values leave the times unchanged
+	assert.NoError(t, filePath.Chtimes(time.Time{}, time.Time{}))
+	fileModTime, err = filePath.ModTime()
+	assert.NoError(t, err)
+	assert.True(t, fileModTime.Equal(modTime))
+
+	assert.Error(t, tempPath.JoinStrings("missing").Chtimes(accessTime, modTime))
+}
+
+func TestPath_Ownership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("numeric ownership is not supported on windows")
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.MinimalStem())
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("file")
+	assert.NoError(t, os.WriteFile(filePath.String(), nil, 0644))
+
+	currentUser, err := user.Current()
+	assert.NoError(t, err)
+
+	currentGroup, err := user.LookupGroupId(strconv.Itoa(os.Getgid()))
+	assert.NoError(t, err)
+
+	t.Run("Chown", func(t *testing.T) {
+		// changing to the current ids is always permitted
+		assert.NoError(t, filePath.Chown(os.Getuid(), os.Getgid()))
+		assert.NoError(t, filePath.Chown(-1, -1))
+		assert.Error(t, tempPath.JoinStrings("missing").Chown(-1, -1))
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		owner, err := filePath.Owner()
+		assert.NoError(t, err)
+		assert.Equal(t, currentUser.Username, owner)
+
+		_, err = tempPath.JoinStrings("missing").Owner()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		group, err := filePath.Group()
+		assert.NoError(t, err)
+		assert.Equal(t, currentGroup.Name, group)
+
+		_, err = tempPath.JoinStrings("missing").Group()
+		assert.ErrorIs(t, err, os.ErrNotExist)
 	})
 }
 
-func TestPath_Root(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: "."},
-		{Input: NewPath(".."), Expect: ".."},
-		{Input: NewPath("/"), Expect: "/"},
-		{Input: NewPath("c:/"), Expect: "c:"},
-		{Input: NewPath("c://"), Expect: "c:"},
-		{Input: NewPath("c:\\"), Expect: "c:"},
-		{Input: NewPath("c:\\\\"), Expect: "c:"},
-		{Input: NewPath("c:/foo"), Expect: "c:"},
-		{Input: NewPath("foo/bar"), Expect: "foo"},
-		{Input: NewPath("foo/bar.js"), Expect: "foo"},
-		{Input: NewPath("/foo/bar.js"), Expect: "/"},
-		{Input: NewPath("bar.js"), Expect: "bar.js"},
-		{Input: NewPath("../bar"), Expect: "../bar"},
-		{Input: NewPath("../../bar.js"), Expect: "../../bar.js"},
+func TestPath_Access(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on windows")
+	}
+
+	tempPath := NewPath(t.TempDir())
+
+	files := map[string]os.FileMode{"executable": 0755, "regular": 0644, "readonly": 0444, "none": 0000}
+	for name, mode := range files {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), nil, mode))
+		assert.NoError(t, os.Chmod(tempPath.JoinStrings(name).String(), mode))
+	}
+
+	// the superuser may read and write any file
+	isRoot := os.Geteuid() == 0
+
+	// the expected slice holds the results of IsReadable, IsWritable and IsExecutable
+	cases := []TestCase[string, []bool]{
+		{Input: "", Expect: []bool{true, true, true}},
+		{Input: "executable", Expect: []bool{true, true, true}},
+		{Input: "regular", Expect: []bool{true, true, false}},
+		{Input: "readonly", Expect: []bool{true, isRoot, false}},
+		{Input: "none", Expect: []bool{isRoot, isRoot, false}},
+		{Input: "missing", Expect: []bool{false, false, false}},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		assert.Equal(t, expect, input.Root())
+	runForResults(t, cases, func(t *testing.T, input string, expect []bool) {
+		assert.Len(t, expect, 3)
+		path := tempPath.JoinStrings(input)
+
+		assert.Equal(t, expect[0], path.IsReadable(), "IsReadable")
+		assert.Equal(t, expect[1], path.IsWritable(), "IsWritable")
+		assert.Equal(t, expect[2], path.IsExecutable(), "IsExecutable")
 	})
 }
 
-func TestPath_AbsoluteAndRelative(t *testing.T) {
+func TestPath_IsHidden(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hidden files are defined by file attributes on windows")
+	}
+
 	cases := []TestCase[*Path, bool]{
 		{Input: NewPath("."), Expect: false},
 		{Input: NewPath(".."), Expect: false},
-		{Input: NewPath("/"), Expect: true},
-		{Input: NewPath("c:"), Expect: false},
-		{Input: NewPath("c:/"), Expect: false},
-		{Input: NewPath("c://"), Expect: false},
-		{Input: NewPath("c:\\"), Expect: false},
-		{Input: NewPath("c:\\\\"), Expect: false},
-		{Input: NewPath("foo/bar"), Expect: false},
-		{Input: NewPath("/foo/bar.js"), Expect: true},
-		{Input: NewPath("bar.js"), Expect: false},
-		{Input: NewPath("../bar"), Expect: false},
-		{Input: NewPath("../../bar.js"), Expect: false},
+		{Input: NewPath("/"), Expect: false},
+		{Input: NewPath("foo"), Expect: false},
+		{Input: NewPath(".foo"), Expect: true},
+		{Input: NewPath("foo/.bar"), Expect: true},
+		{Input: NewPath(".foo/bar"), Expect: false},
+		{Input: NewPath("/foo/.bar.txt"), Expect: true},
+		{Input: NewPath("../.bar"), Expect: true},
 	}
 
 	for i, testCase := range cases {
@@ -473,405 +1129,4016 @@ func TestPath_AbsoluteAndRelative(t *testing.T) {
 	}
 
 	runForResults(t, cases, func(t *testing.T, input *Path, expect bool) {
-		assert.Equal(t, expect, input.IsAbsolute())
-		assert.Equal(t, !expect, input.IsRelative())
+		hidden, err := input.IsHidden()
+		assert.NoError(t, err)
+		assert.Equal(t, expect, hidden)
 	})
 }
 
-func TestPath_RelativeTo(t *testing.T) {
-	cases := []TestCase[[]*Path, *Path]{
-		{Input: []*Path{NewPath("/a/b"), NewPath("/")}, Expect: NewPath("a/b")},
-		{Input: []*Path{NewPath("/a/b"), NewPath("/a")}, Expect: NewPath("b")},
-		{Input: []*Path{NewPath("a/b"), NewPath("a")}, Expect: NewPath("b")},
-		{Input: []*Path{NewPath("a/b/d"), NewPath("a/b/c")}, Expect: NewPath("../d")},
-		{Input: []*Path{NewPath("/b"), NewPath("/a")}, Expect: NewPath("../b")},
-		{Input: []*Path{NewPath("/b/d"), NewPath("/a/c")}, Expect: NewPath("../../b/d")},
-		{Input: []*Path{NewPath("/"), NewPath("/a/b")}, Expect: NewPath("../..")},
-		{Input: []*Path{NewPath(""), NewPath("/a/b")}, Error: true},
-		{Input: []*Path{NewPath("../"), NewPath("/a/b")}, Error: true},
-		{Input: []*Path{NewPath("../b"), NewPath("a/b")}, Expect: NewPath("../../../b")},
-		{Input: []*Path{NewPath("a/b\\ whitespace/c"), NewPath("a/d")}, Expect: NewPath("../b whitespace/c")},
-	}
+func TestPath_TreeSize(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	for i := range cases {
-		cases[i].Name = fmt.Sprintf("[%d]", i+1)
+	tree := tempPath.JoinStrings("tree")
+	outside := tempPath.JoinStrings("outside")
+	assert.NoError(t, tree.JoinStrings("nested").MkdirAll(0755))
+	assert.NoError(t, outside.Mkdir(0755))
+
+	files := map[string]int{"tree/a": 10, "tree/nested/b": 20, "outside/c": 100}
+	for name, size := range files {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), make([]byte, size), 0644))
 	}
 
-	runForResultsE(t, cases, func(t *testing.T, input []*Path, expect *Path, error bool) {
-		assert.Equal(t, len(input), 2)
+	assert.NoError(t, os.Symlink("../outside", tree.JoinStrings("outside-link").String()))
+	assert.NoError(t, os.Symlink("../outside/c", tree.JoinStrings("file-link").String()))
+	assert.NoError(t, os.Symlink("..", tree.JoinStrings("nested", "cycle").String()))
+	assert.NoError(t, os.Symlink("missing", tree.JoinStrings("broken").String()))
+
+	cases := []TestCase[*Path, int64]{
+		{Input: tree, Expect: 30},
+		{Input: tree.JoinStrings("nested", "b"), Expect: 20},
+		{Input: tree.JoinStrings("outside-link"), Expect: 100},
+		{Input: tree.JoinStrings("file-link"), Expect: 100},
+		{Input: tree.JoinStrings("broken"), Error: true},
+		{Input: tempPath.JoinStrings("missing"), Error: true},
+	}
 
-		basePath := input[0]
-		otherPath := input[1]
-		relativePath, err := basePath.RelativeTo(otherPath)
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
 
+	runForResultsE(t, cases, func(t *testing.T, input *Path, expect int64, error bool) {
+		size, err := input.TreeSize(TreeSizeOptions{})
 		assert.Equal(t, error, err != nil)
+
 		if !error {
-			assert.Equal(t, expect, relativePath)
+			assert.Equal(t, expect, size)
 		}
 	})
+
+	t.Run("follow symlinks", func(t *testing.T) {
+		// the outside directory is counted once, the linked file is counted separately
+		size, err := tree.TreeSize(TreeSizeOptions{FollowSymlinks: true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(230), size)
+	})
+
+	t.Run("disk usage", func(t *testing.T) {
+		size, err := tree.TreeSize(TreeSizeOptions{DiskUsage: true})
+		assert.NoError(t, err)
+		assert.Positive(t, size)
+	})
 }
 
-func TestPath_Absolute(t *testing.T) {
-	wdPath, err := NewCwd()
-	assert.NoError(t, err)
+func TestPath_TreeStats(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	cases := []TestCase[*Path, *Path]{
-		{Input: NewPath("."), Expect: wdPath.JoinStrings(".")},
-		{Input: NewPath("foo"), Expect: wdPath.JoinStrings("foo")},
-		{Input: NewPath("foo/bar"), Expect: wdPath.JoinStrings("foo/bar")},
-		{Input: NewPath("/"), Expect: NewPath("/")},
-		{Input: NewPath("/foo"), Expect: NewPath("/foo")},
+	assert.NoError(t, tempPath.JoinStrings("a", "b").MkdirAll(0755))
+	assert.NoError(t, tempPath.JoinStrings("c").Mkdir(0755))
+	files := map[string]int{"file": 10, "a/file": 20, "a/b/file": 30}
+	for name, size := range files {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), make([]byte, size), 0644))
+	}
+	assert.NoError(t, os.Symlink("a", tempPath.JoinStrings("link").String()))
+
+	cases := []TestCase[string, TreeStats]{
+		{Input: "", Expect: TreeStats{Files: 3, Dirs: 3, Symlinks: 1, Bytes: 60}},
+		{Input: "a", Expect: TreeStats{Files: 2, Dirs: 1, Bytes: 50}},
+		{Input: "c", Expect: TreeStats{}},
+		{Input: "file", Expect: TreeStats{Files: 1, Bytes: 10}},
+		{Input: "link", Expect: TreeStats{Symlinks: 1}},
+		{Input: "missing", Error: true},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect *Path) {
-		absolutePath, err := input.Absolute()
-		assert.NoError(t, err)
+	runForResultsE(t, cases, func(t *testing.T, input string, expect TreeStats, error bool) {
+		stats, err := tempPath.JoinStrings(input).TreeStats()
+		assert.Equal(t, error, err != nil)
 
-		assert.Equal(t, expect, absolutePath)
+		if !error {
+			assert.Equal(t, expect, stats)
+		}
 	})
 }
 
-func TestPath_AbsoluteTo(t *testing.T) {
-	cases := []TestCase[[]*Path, *Path]{
-		{Input: []*Path{NewPath("."), NewPath(".")}, Error: true},
-		{Input: []*Path{NewPath("/"), NewPath(".")}, Expect: NewPath("/")},
-		{Input: []*Path{NewPath("."), NewPath("/foo")}, Expect: NewPath("/foo")},
-		{Input: []*Path{NewPath("hello"), NewPath("/foo")}, Expect: NewPath("/foo/hello")},
-		{Input: []*Path{NewPath("../hello"), NewPath("/foo")}, Expect: NewPath("/hello")},
-		{Input: []*Path{NewPath("hello/bar"), NewPath("/foo")}, Expect: NewPath("/foo/hello/bar")},
-	}
-
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
-	}
-
-	runForResultsE(t, cases, func(t *testing.T, input []*Path, expect *Path, error bool) {
-		assert.Equal(t, len(input), 2)
-
-		base := input[0]
-		other := input[1]
-		absolutePath, err := base.AbsoluteTo(other)
-		assert.Equal(t, error, err != nil)
-
-		if !error {
-			assert.Equal(t, expect, absolutePath)
-		}
-	})
-}
-
-func TestPath_Resolve(t *testing.T) {
-	// temporary directory
-	tempPath := NewPath(t.TempDir()) // creates temporary directory at /var
-
-	// temporary file paths
-	originalFile := "originalFile"
-	symlinkFile := "symlinkedFile"
+func TestPath_DeviceIDInode(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	originalFilePath := NewPath("/private").JoinStrings(tempPath.String(), originalFile) // /var is actually a symlink to /private/var
-	symlinkFilePath := tempPath.JoinStrings(symlinkFile)
+	filePath := tempPath.JoinStrings("file")
+	otherPath := tempPath.JoinStrings("other")
+	hardLinkPath := tempPath.JoinStrings("hardlink")
+	symlinkPath := tempPath.JoinStrings("symlink")
 
-	// create original file
-	file, err := os.OpenFile(originalFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
-	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filePath.String(), nil, 0644))
+	assert.NoError(t, os.WriteFile(otherPath.String(), nil, 0644))
+	assert.NoError(t, os.Link(filePath.String(), hardLinkPath.String()))
+	assert.NoError(t, os.Symlink("file", symlinkPath.String()))
 
-	err = file.Close()
+	fileDev, err := filePath.DeviceID()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("file identifiers are not supported on this platform")
+	}
 	assert.NoError(t, err)
 
-	// create symlink
-	err = os.Symlink(originalFilePath.String(), symlinkFilePath.String())
+	fileIno, err := filePath.Inode()
 	assert.NoError(t, err)
 
-	cases := []TestCase[*Path, *Path]{
-		{Input: NewPath("."), Expect: NewPath(".")},
-		{Input: NewPath("./foo"), Error: true},
-		{Input: NewPath("/"), Expect: NewPath("/")},
-		{Input: originalFilePath, Expect: originalFilePath},
-		{Input: symlinkFilePath, Expect: originalFilePath},
+	for _, path := range []*Path{otherPath, hardLinkPath, symlinkPath, tempPath} {
+		dev, err := path.DeviceID()
+		assert.NoError(t, err)
+		assert.Equal(t, fileDev, dev, "same device")
 	}
 
-	for i, testCase := range cases {
-		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	for _, path := range []*Path{hardLinkPath, symlinkPath} {
+		ino, err := path.Inode()
+		assert.NoError(t, err)
+		assert.Equal(t, fileIno, ino, "same inode")
 	}
 
-	runForResultsE(t, cases, func(t *testing.T, input *Path, expect *Path, error bool) {
-		resolvedPath, err := input.Resolve()
-		assert.Equal(t, error, err != nil)
+	otherIno, err := otherPath.Inode()
+	assert.NoError(t, err)
+	assert.NotEqual(t, fileIno, otherIno)
 
-		if !error {
-			assert.Equal(t, expect, resolvedPath)
-		}
-	})
+	_, err = tempPath.JoinStrings("missing").DeviceID()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = tempPath.JoinStrings("missing").Inode()
+	assert.ErrorIs(t, err, os.ErrNotExist)
 }
 
-func TestPath_Joins(t *testing.T) {
-	cases := []TestCase[[]string, *Path]{
-		{Input: []string{"/", "."}, Expect: NewPath("/")},
-		{Input: []string{"/", "foo"}, Expect: NewPath("/foo")},
-		{Input: []string{"/", "../"}, Expect: NewPath("/")},
-		{Input: []string{"/", "../b"}, Expect: NewPath("/b")},
-		{Input: []string{"a", "b"}, Expect: NewPath("a/b")},
-		{Input: []string{"a", "../b"}, Expect: NewPath("b")},
-		{Input: []string{"../a", "../b"}, Expect: NewPath("../b")},
-		{Input: []string{"../a", "../../b"}, Expect: NewPath("../../b")},
+func TestPath_NLinks(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("single").String(), nil, 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("linked").String(), nil, 0644))
+	assert.NoError(t, os.Link(tempPath.JoinStrings("linked").String(), tempPath.JoinStrings("hardlink-1").String()))
+	assert.NoError(t, os.Link(tempPath.JoinStrings("linked").String(), tempPath.JoinStrings("hardlink-2").String()))
+	assert.NoError(t, os.Symlink("linked", tempPath.JoinStrings("symlink").String()))
+
+	cases := []TestCase[string, uint64]{
+		{Input: "single", Expect: 1},
+		{Input: "linked", Expect: 3},
+		{Input: "hardlink-1", Expect: 3},
+		{Input: "symlink", Expect: 3},
+		{Input: "missing", Error: true},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
-		assert.True(t, len(input) > 0)
-
-		basePath := NewPath(input[0])
-		joinedStrPath := basePath.JoinStrings(input[1:]...)
-
-		var strCvtPath []*Path = nil
-		for _, pathStr := range input[1:] {
-			strCvtPath = append(strCvtPath, NewPath(pathStr))
+	runForResultsE(t, cases, func(t *testing.T, input string, expect uint64, error bool) {
+		links, err := tempPath.JoinStrings(input).NLinks()
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("link counts are not supported on this platform")
 		}
-		joinedPathsPath := basePath.Join(strCvtPath...)
 
-		assert.Equal(t, expect, joinedStrPath)
-		assert.Equal(t, expect, joinedPathsPath)
+		assert.Equal(t, error, err != nil)
+		if !error {
+			assert.Equal(t, expect, links)
+		}
 	})
 }
 
-func TestPath_GlobContains(t *testing.T) {
-
-	// NOTICE:
-	// This test only tests the existence check mechanism and
-	// matched filepath string conversions
+func TestPath_DiskUsage(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	// temporary directory
-	tempPath := NewPath(t.TempDir()) // creates temporary directory at /var
+	usage, err := tempPath.DiskUsage()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("disk usage is not supported on this platform")
+	}
 
-	// create a file
-	existingFile := "foo"
-	existingFilePath := tempPath.JoinStrings(existingFile)
-	file, err := os.OpenFile(existingFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
 	assert.NoError(t, err)
+	assert.Positive(t, usage.Total)
+	assert.LessOrEqual(t, usage.Used, usage.Total)
+	assert.LessOrEqual(t, usage.Available, usage.Total)
 
-	err = file.Close()
-	assert.NoError(t, err)
+	_, err = tempPath.JoinStrings("missing").DiskUsage()
+	assert.Error(t, err)
+}
 
-	// create subdirectory
-	existingSubdirectory := "bar"
-	existingSubdirectoryPath := tempPath.JoinStrings(existingSubdirectory)
-	err = os.Mkdir(existingSubdirectoryPath.String(), 0777)
-	assert.NoError(t, err)
+func TestPath_SpecialFiles(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	// create file in subdirectory
-	existingSubdirFile := "baz"
-	existingSubdirFilePath := existingSubdirectoryPath.JoinStrings(existingSubdirFile)
-	subdirFile, err := os.OpenFile(existingSubdirFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
-	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("file").String(), nil, 0644))
 
-	err = subdirFile.Close()
-	assert.NoError(t, err)
+	listener, err := net.Listen("unix", tempPath.JoinStrings("socket").String())
+	if err == nil {
+		defer listener.Close()
+	}
 
-	// test cases; the first string is the root path
-	// starting at the temporary directory, the second
-	// string is the pattern to search for
+	// the expected slice holds the results of IsFifo, IsSocket, IsBlockDevice and IsCharDevice
+	cases := []TestCase[*Path, []bool]{
+		{Input: tempPath, Expect: []bool{false, false, false, false}},
+		{Input: tempPath.JoinStrings("file"), Expect: []bool{false, false, false, false}},
+		{Input: tempPath.JoinStrings("missing"), Expect: []bool{false, false, false, false}},
+	}
 
-	// TODO support double asterisks globbing, without adding dependencies
-	// see https://github.com/golang/go/issues/11862
-	cases := []TestCase[[]string, int]{
-		{Input: []string{"", ""}, Error: true},
-		{Input: []string{"", "  "}, Error: true},
-		{Input: []string{"", "  \t"}, Error: true},
-		{Input: []string{"", " \t \n  "}, Error: true},
-		{Input: []string{"", "*"}, Expect: 2},
-		{Input: []string{"", "/*"}, Expect: 2},
-		{Input: []string{"", "**"}, Expect: 2},
-		{Input: []string{"", "*/*"}, Expect: 1},
-		{Input: []string{"", "bar/*"}, Expect: 1},
-		{Input: []string{"", "bar/bar"}, Expect: 0},
-		{Input: []string{"", "bar/baz"}, Expect: 1},
-		{Input: []string{"", "bar/*z"}, Expect: 1},
-		{Input: []string{"", "bat/*z"}, Expect: 0},
+	if err == nil {
+		cases = append(cases, TestCase[*Path, []bool]{Input: tempPath.JoinStrings("socket"), Expect: []bool{false, true, false, false}})
+	}
+
+	if NewPath("/dev/null").Exists() {
+		cases = append(cases, TestCase[*Path, []bool]{Input: NewPath("/dev/null"), Expect: []bool{false, false, false, true}})
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResultsE(t, cases, func(t *testing.T, input []string, expect int, error bool) {
-		assert.Len(t, input, 2)
-		assert.GreaterOrEqual(t, expect, 0)
-
-		path := tempPath.JoinStrings(input[0])
-		pattern := input[1]
-
-		matches, globErr := path.Glob(pattern)
-		contains, containsErr := path.Contains(pattern)
-		containsB := path.BContains(pattern)
-
-		assert.Equal(t, error, globErr != nil)
-		assert.Equal(t, error, containsErr != nil)
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []bool) {
+		assert.Len(t, expect, 4)
 
-		if !error {
-			assert.Len(t, matches, expect)
-			assert.Equal(t, expect != 0, contains)
-			assert.Equal(t, contains, containsB)
-		}
+		assert.Equal(t, expect[0], input.IsFifo(), "IsFifo")
+		assert.Equal(t, expect[1], input.IsSocket(), "IsSocket")
+		assert.Equal(t, expect[2], input.IsBlockDevice(), "IsBlockDevice")
+		assert.Equal(t, expect[3], input.IsCharDevice(), "IsCharDevice")
 	})
 }
 
-func TestPath_CaseSensitivity(t *testing.T) {
-	// NOTICE:
-	// This function is difficult to test, as this is dependent on the underlying file system.
-	// This also means we cannot test os-dependent, because Linux-based operating systems
-	// support multiple file systems.
+func TestPath_IsJunction(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
 
-	// This stability of this function will result by using and testing it.
-	// (which is very dirty)
-}
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("file").String(), nil, 0644))
+	assert.NoError(t, os.Symlink("file", tempPath.JoinStrings("link").String()))
 
-func TestPath_Equals(t *testing.T) {
-	cases := []TestCase[[]string, bool]{
-		{Input: []string{"", ""}, Expect: true},
-		{Input: []string{"", "a"}, Expect: false},
-		{Input: []string{"foo", "foo"}, Expect: true},
-		{Input: []string{"   foo", "foo"}, Expect: true},
-		{Input: []string{"foo", "Foo"}, Expect: false},
-		{Input: []string{"./foo", "foo"}, Expect: true},
-		{Input: []string{"./foo", "\tfoo"}, Expect: true},
-		{Input: []string{"./foo", "/foo"}, Expect: false},
-		{Input: []string{"/foo", "/foo"}, Expect: true},
-		{Input: []string{"/foo", "/foo\n"}, Expect: true},
-		{Input: []string{"/foo", "/Foo"}, Expect: false},
+	cases := []TestCase[string, bool]{
+		{Input: "", Expect: false},
+		{Input: "file", Expect: false},
+		{Input: "link", Expect: false},
+		{Input: "missing", Error: true},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
-		assert.Len(t, input, 2)
-
-		basePath := NewPath(input[0])
-		pathEquals := basePath.Equals(NewPath(input[1]))
-		stringEquals := basePath.EqualsString(input[1])
+	runForResultsE(t, cases, func(t *testing.T, input string, expect bool, error bool) {
+		isJunction, err := tempPath.JoinStrings(input).IsJunction()
+		assert.Equal(t, error, err != nil)
 
-		assert.Equal(t, expect, pathEquals)
-		assert.Equal(t, expect, stringEquals)
+		if !error {
+			assert.Equal(t, expect, isJunction)
+		}
 	})
 }
 
-func TestPath_EqualsCi(t *testing.T) {
-	cases := []TestCase[[]string, bool]{
-		{Input: []string{"", ""}, Expect: true},
-		{Input: []string{"", "a"}, Expect: false},
-		{Input: []string{"foo", "foo"}, Expect: true},
-		{Input: []string{"   foo", "foo"}, Expect: true},
-		{Input: []string{"foo", "Foo"}, Expect: true},
-		{Input: []string{"./foo", "foo"}, Expect: true},
-		{Input: []string{"./foo", "\tfoo"}, Expect: true},
-		{Input: []string{"./foo", "/foo"}, Expect: false},
-		{Input: []string{"/foo", "/foo"}, Expect: true},
-		{Input: []string{"/foo", "/foo\n"}, Expect: true},
-		{Input: []string{"/foo", "/Foo"}, Expect: true},
+func TestPath_Parent(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath(".."), Expect: "."},
+		{Input: NewPath("/"), Expect: "/"},
+		{Input: NewPath("foo/bar"), Expect: "foo"},
+		{Input: NewPath("foo/bar.js"), Expect: "foo"},
+		{Input: NewPath("/foo/bar.js"), Expect: "/foo"},
+		{Input: NewPath("bar.js"), Expect: "."},
+		{Input: NewPath("../bar.js"), Expect: ".."},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
-		assert.Len(t, input, 2)
-
-		basePath := NewPath(input[0])
-		pathEquals := basePath.EqualsCi(NewPath(input[1]))
-		stringEquals := basePath.EqualsStringCi(input[1])
-
-		assert.Equal(t, expect, pathEquals)
-		assert.Equal(t, expect, stringEquals)
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Parent().path)
 	})
 }
 
-func TestPath_EqualsFS(t *testing.T) {
-	// NOTICE:
-	// This is difficult to test, as it is depending on IsCaseSensitiveFs()
-}
-
-func TestPath_ToPosix(t *testing.T) {
-	cases := []TestCase[*Path, string]{
-		{Input: NewPath("."), Expect: "."},
-		{Input: NewPath(".."), Expect: ".."},
-		{Input: NewPath("/foo"), Expect: "/foo"},
-		{Input: NewPath("\\\\foo"), Expect: "/foo"},
-		{Input: NewPath("\\\\foo\\bar"), Expect: "/foo/bar"},
-		{Input: NewPath("\\\\foo\\\\bar"), Expect: "/foo/bar"},
-		{Input: NewPath("/foo/with\\ whitespace"), Expect: "/foo/with\\ whitespace"},
-		{Input: NewPath("\\foo\\with\\ whitespace"), Expect: "/foo/with\\ whitespace"},
-		{Input: NewPath("\\\\foo\\\\with\\ whitespace"), Expect: "/foo/with\\ whitespace"},
+func TestPath_Depth(t *testing.T) {
+	cases := []TestCase[*Path, int]{
+		{Input: NewPath("."), Expect: 0},
+		{Input: NewPath(""), Expect: 0},
+		{Input: NewPath("/"), Expect: 0},
+		{Input: NewPath(".."), Expect: 1},
+		{Input: NewPath("foo"), Expect: 1},
+		{Input: NewPath("/foo"), Expect: 1},
+		{Input: NewPath("foo/bar/"), Expect: 2},
+		{Input: NewPath("/foo/../bar/baz"), Expect: 2},
+		{Input: NewPath("../../foo"), Expect: 3},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
-		toPosix := input.ToPosix()
-
-		assert.Equal(t, expect, toPosix)
+	runForResults(t, cases, func(t *testing.T, input *Path, expect int) {
+		assert.Equal(t, expect, input.Depth())
 	})
 }
 
-func TestPath_WithName(t *testing.T) {
-	cases := []TestCase[[]string, *Path]{
-		{Input: []string{"", "foo"}, Expect: NewPath("foo")},
-		{Input: []string{"/", "foo"}, Expect: NewPath("/foo")},
-		{Input: []string{"../", "foo"}, Expect: NewPath("foo")},
-		{Input: []string{"../..", "foo"}, Expect: NewPath("../foo")},
-		{Input: []string{"foo/bar", "foo"}, Expect: NewPath("foo/foo")},
-		{Input: []string{"/foo/bar", "foo"}, Expect: NewPath("/foo/foo")},
-		{Input: []string{"foo/file.txt", "bar.txt"}, Expect: NewPath("foo/bar.txt")},
-		{Input: []string{"foo/.txt", ".json"}, Expect: NewPath("foo/.json")},
-		{Input: []string{"/foo/.txt", ".json"}, Expect: NewPath("/foo/.json")},
+func TestPath_Parents(t *testing.T) {
+	cases := []TestCase[*Path, []string]{
+		{Input: NewPath("."), Expect: []string{}},
+		{Input: NewPath(".."), Expect: []string{"."}},
+		{Input: NewPath("/"), Expect: []string{}},
+		{Input: NewPath("foo"), Expect: []string{"."}},
+		{Input: NewPath("foo/bar/baz"), Expect: []string{"foo/bar", "foo", "."}},
+		{Input: NewPath("/foo/bar.js"), Expect: []string{"/foo", "/"}},
+		{Input: NewPath("../../bar.js"), Expect: []string{"../..", "..", "."}},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
-		assert.True(t, len(input) == 2)
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
+		parents := []string{}
+		for _, parent := range input.Parents() {
+			parents = append(parents, parent.path)
+		}
 
-		// call function and assert
-		path := NewPath(input[0])
-		changedName := path.WithName(input[1])
+		assert.Equal(t, expect, parents)
+	})
+}
 
-		assert.Equal(t, expect, changedName)
+func TestPath_Parts(t *testing.T) {
+	cases := []TestCase[*Path, []string]{
+		{Input: NewPath("."), Expect: []string{"."}},
+		{Input: NewPath(".."), Expect: []string{".."}},
+		{Input: NewPath("/"), Expect: []string{}},
+		{Input: NewPath("foo/bar"), Expect: []string{"foo", "bar"}},
+		{Input: NewPath("foo/bar.js"), Expect: []string{"foo", "bar.js"}},
+		{Input: NewPath("/foo/bar.js"), Expect: []string{"foo", "bar.js"}},
+		{Input: NewPath("bar.js"), Expect: []string{"bar.js"}},
+		{Input: NewPath("../bar.js"), Expect: []string{"..", "bar.js"}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
+		assert.Equal(t, expect, input.Parts())
 	})
 }
 
-func TestPath_Copy(t *testing.T) {
-	cases := []TestCase[*Path, interface{}]{
-		{Input: NewPath("foo/bar")},
-		{Input: NewPath("../foo/bar")},
-		{Input: NewPath("..")},
-		{Input: NewPath("/foo")},
+func TestPath_Base(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath(".."), Expect: ".."},
+		{Input: NewPath("/"), Expect: "/"},
+		{Input: NewPath("foo/bar"), Expect: "bar"},
+		{Input: NewPath("foo/bar.js"), Expect: "bar.js"},
+		{Input: NewPath("/foo/bar.js"), Expect: "bar.js"},
+		{Input: NewPath("bar.js"), Expect: "bar.js"},
+		{Input: NewPath("../bar.js"), Expect: "bar.js"},
 	}
 
 	for i, testCase := range cases {
 		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
 	}
 
-	runForResults(t, cases, func(t *testing.T, input *Path, expect interface{}) {
-		pointerCopy := input
-		copiedPath := input.Copy()
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Base())
+	})
+}
 
-		// compare pointers
-		assert.True(t, input == pointerCopy)
-		assert.False(t, input == copiedPath)
+func TestPath_Split(t *testing.T) {
+	cases := []TestCase[*Path, []string]{
+		{Input: NewPath("."), Expect: []string{".", "."}},
+		{Input: NewPath(".."), Expect: []string{".", ".."}},
+		{Input: NewPath("/"), Expect: []string{"/", ""}},
+		{Input: NewPath("foo/bar"), Expect: []string{"foo", "bar"}},
+		{Input: NewPath("foo/bar.js"), Expect: []string{"foo", "bar.js"}},
+		{Input: NewPath("/foo/bar.js"), Expect: []string{"/foo", "bar.js"}},
+		{Input: NewPath("bar.js"), Expect: []string{".", "bar.js"}},
+		{Input: NewPath("../bar.js"), Expect: []string{"..", "bar.js"}},
+	}
 
-		// ensure copied path has same contents as original
-		assert.Equal(t, input, copiedPath)
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
+		assert.Equal(t, len(expect), 2)
+
+		inputPartParent, inputPartBase := input.Split()
+
+		assert.Equal(t, expect[0], inputPartParent.path, "Parent")
+		assert.Equal(t, expect[1], inputPartBase, "Base")
+	})
+}
+
+func TestPath_Extension(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: ""},
+		{Input: NewPath(".."), Expect: ""},
+		{Input: NewPath("/"), Expect: ""},
+		{Input: NewPath("foo/bar"), Expect: ""},
+		{Input: NewPath("foo/bar.js"), Expect: ".js"},
+		{Input: NewPath("/foo/bar.js"), Expect: ".js"},
+		{Input: NewPath("bar.js"), Expect: ".js"},
+		{Input: NewPath("../bar.js"), Expect: ".js"},
+		{Input: NewPath("../bar.js.foo"), Expect: ".foo"},
+		{Input: NewPath(".bar.js"), Expect: ".js"},
+		{Input: NewPath("..bar.js"), Expect: ".js"},
+		{Input: NewPath("..bar"), Expect: ""},
+		{Input: NewPath("...bar"), Expect: ""},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Extension())
+	})
+}
+
+func TestPath_Extensions(t *testing.T) {
+	cases := []TestCase[*Path, []string]{
+		{Input: NewPath("."), Expect: []string{}},
+		{Input: NewPath(".."), Expect: []string{}},
+		{Input: NewPath("/"), Expect: []string{}},
+		{Input: NewPath("foo/bar"), Expect: []string{}},
+		{Input: NewPath("foo/bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("/foo/bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("../bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("../bar.js.foo"), Expect: []string{".js", ".foo"}},
+		{Input: NewPath(".bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("..bar.js"), Expect: []string{".js"}},
+		{Input: NewPath("..bar"), Expect: []string{}},
+		{Input: NewPath("...bar"), Expect: []string{}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []string) {
+		assert.Equal(t, expect, input.Extensions())
+	})
+}
+
+func TestPath_Stem(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: ""},
+		{Input: NewPath(".."), Expect: ".."},
+		{Input: NewPath("/"), Expect: ""},
+		{Input: NewPath("foo/bar"), Expect: "bar"},
+		{Input: NewPath("foo/bar.js"), Expect: "bar"},
+		{Input: NewPath("/foo/bar.js"), Expect: "bar"},
+		{Input: NewPath("bar.js"), Expect: "bar"},
+		{Input: NewPath("../bar.js"), Expect: "bar"},
+		{Input: NewPath("../bar.js.foo"), Expect: "bar.js"},
+		{Input: NewPath(".bar.js"), Expect: ".bar"},
+		{Input: NewPath("..bar.js"), Expect: "..bar"},
+		{Input: NewPath("..bar"), Expect: "..bar"},
+		{Input: NewPath("...bar"), Expect: "...bar"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Stem())
+	})
+}
+
+func TestPath_MinimalStem(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: ""},
+		{Input: NewPath(".."), Expect: ".."},
+		{Input: NewPath("/"), Expect: ""},
+		{Input: NewPath("foo/bar"), Expect: "bar"},
+		{Input: NewPath("foo/bar.js"), Expect: "bar"},
+		{Input: NewPath("/foo/bar.js"), Expect: "bar"},
+		{Input: NewPath("bar.js"), Expect: "bar"},
+		{Input: NewPath("../bar.js"), Expect: "bar"},
+		{Input: NewPath("../bar.js.foo"), Expect: "bar"},
+		{Input: NewPath(".bar.js"), Expect: ".bar"},
+		{Input: NewPath("..bar.js"), Expect: "..bar"},
+		{Input: NewPath("..bar"), Expect: "..bar"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.MinimalStem())
+	})
+}
+
+func TestPath_Root(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath(".."), Expect: ".."},
+		{Input: NewPath("/"), Expect: "/"},
+		{Input: NewPath("c:/"), Expect: "c:"},
+		{Input: NewPath("c://"), Expect: "c:"},
+		{Input: NewPath("c:\\"), Expect: "c:"},
+		{Input: NewPath("c:\\\\"), Expect: "c:"},
+		{Input: NewPath("c:/foo"), Expect: "c:"},
+		{Input: NewPath("foo/bar"), Expect: "foo"},
+		{Input: NewPath("foo/bar.js"), Expect: "foo"},
+		{Input: NewPath("/foo/bar.js"), Expect: "/"},
+		{Input: NewPath("bar.js"), Expect: "bar.js"},
+		{Input: NewPath("../bar"), Expect: "../bar"},
+		{Input: NewPath("../../bar.js"), Expect: "../../bar.js"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Root())
+	})
+}
+
+func TestPath_Drive(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("/foo/bar"), Expect: ""},
+		{Input: NewPath("foo/bar"), Expect: ""},
+		{Input: NewPath("."), Expect: ""},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = append(cases,
+			TestCase[*Path, string]{Input: NewPath(`C:\foo`), Expect: "C:"},
+			TestCase[*Path, string]{Input: NewPath(`c:foo`), Expect: "c:"},
+			TestCase[*Path, string]{Input: NewPath(`\\server\share\foo`), Expect: `\\server\share`},
+		)
+	} else {
+		cases = append(cases, TestCase[*Path, string]{Input: NewPath(`C:\foo`), Expect: ""})
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Drive())
+	})
+}
+
+func TestPath_Anchor(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("/foo/bar"), Expect: "/"},
+		{Input: NewPath("/"), Expect: "/"},
+		{Input: NewPath("foo/bar"), Expect: ""},
+		{Input: NewPath("../foo"), Expect: ""},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = []TestCase[*Path, string]{
+			{Input: NewPath(`C:\foo`), Expect: `C:\`},
+			{Input: NewPath(`C:foo`), Expect: "C:"},
+			{Input: NewPath(`\\server\share\foo`), Expect: `\\server\share\`},
+			{Input: NewPath(`foo\bar`), Expect: ""},
+		}
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.Anchor())
+	})
+}
+
+func TestPath_UNCComponents(t *testing.T) {
+	type uncResult struct {
+		server string
+		share  string
+		ok     bool
+	}
+
+	cases := []TestCase[string, uncResult]{
+		{Input: `\\server\share\dir`, Expect: uncResult{"server", "share", true}},
+		{Input: `\\server\share`, Expect: uncResult{"server", "share", true}},
+		{Input: `\\server\share\dir\..\..`, Expect: uncResult{"server", "share", true}},
+		{Input: `\\server`, Expect: uncResult{}},
+		{Input: `\\?\C:\dir`, Expect: uncResult{}},
+		{Input: `C:\dir`, Expect: uncResult{}},
+		{Input: `/dir`, Expect: uncResult{}},
+		{Input: `dir`, Expect: uncResult{}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect uncResult) {
+		// UNC paths only exist on Windows
+		if runtime.GOOS != "windows" {
+			expect = uncResult{}
+		}
+
+		server, share, ok := NewPath(input).UNCComponents()
+		assert.Equal(t, expect, uncResult{server, share, ok})
+	})
+
+	t.Run("cleaning", func(t *testing.T) {
+		expect := map[string]string{
+			`\\server\share\dir`:       "/server/share/dir",
+			`\\server\share\`:          "/server/share",
+			`\\server\share\a\..\..\b`: "/server/b",
+			"//usr/lib":                "/usr/lib",
+		}
+
+		for input, cleaned := range expect {
+			if runtime.GOOS == "windows" {
+				cleaned = filepath.Clean(input)
+			}
+
+			assert.Equal(t, cleaned, NewPath(input).path, input)
+			assert.Equal(t, NewPath(input), NewPath(NewPath(input).path), input)
+		}
+
+		assert.True(t, NewPath("//usr/lib").Equals(NewPath("/usr/lib")))
+	})
+
+	t.Run("operations", func(t *testing.T) {
+		if runtime.GOOS != "windows" {
+			t.Skip("UNC paths only exist on windows")
+		}
+
+		path := NewPath(`\\server\share\dir`)
+
+		assert.Equal(t, NewPath(`\\server\share\dir\x`), path.JoinStrings("x"))
+		assert.Equal(t, NewPath(`\\server\share\dir\x`), path.Join(NewPath("x")))
+		assert.Equal(t, NewPath(`\\server\share\x`), path.JoinStrings("..", "..", "x"))
+		assert.Equal(t, NewPath(`\\server\share`), path.Parent())
+		assert.Equal(t, NewPath(`\\server\share`), path.Parent().Parent())
+		assert.Equal(t, []*Path{NewPath(`\\server\share`)}, path.Parents())
+	})
+}
+
+func TestSetLongPathPrefixing(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	short := tempPath.JoinStrings("short")
+	long := tempPath.JoinStrings(strings.Repeat("x", 100), strings.Repeat("y", 100), strings.Repeat("z", 100))
+
+	assert.Equal(t, long.path, systemPath(long.path))
+
+	SetLongPathPrefixing(true)
+	defer SetLongPathPrefixing(false)
+
+	assert.Equal(t, short.path, systemPath(short.path))
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, long.path, systemPath(long.path))
+		return
+	}
+
+	assert.Equal(t, `\\?\`+long.path, systemPath(long.path))
+
+	// the Windows API is called with the extended-length form
+	assert.NoError(t, long.MkdirAll(0755))
+	hidden, err := long.IsHidden()
+	assert.NoError(t, err)
+	assert.False(t, hidden)
+
+	_, err = long.Inode()
+	assert.NoError(t, err)
+}
+
+func TestPath_ToExtendedLength(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		for _, input := range []string{"/foo/bar", "foo", `\\server\share\dir`} {
+			assert.Equal(t, NewPath(input), NewPath(input).ToExtendedLength(), input)
+		}
+		return
+	}
+
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	cases := []TestCase[string, string]{
+		{Input: `C:\foo\bar`, Expect: `\\?\C:\foo\bar`},
+		{Input: `\\server\share\dir`, Expect: `\\?\UNC\server\share\dir`},
+		{Input: `\\?\C:\foo`, Expect: `\\?\C:\foo`},
+		{Input: `foo`, Expect: `\\?\` + filepath.Join(cwd, "foo")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect string) {
+		assert.Equal(t, expect, NewPath(input).ToExtendedLength().path)
+	})
+}
+
+func TestPath_IsReserved(t *testing.T) {
+	cases := []TestCase[string, bool]{
+		{Input: "CON", Expect: true},
+		{Input: "con", Expect: true},
+		{Input: "dir/nul.txt", Expect: true},
+		{Input: "Aux.tar.gz", Expect: true},
+		{Input: "PRN ", Expect: true},
+		{Input: "COM1", Expect: true},
+		{Input: "lpt9.log", Expect: true},
+		{Input: "COM¹", Expect: true},
+		{Input: "CONIN$", Expect: true},
+		{Input: "COM0", Expect: false},
+		{Input: "COM10", Expect: false},
+		{Input: "CONSOLE", Expect: false},
+		{Input: "nul/file", Expect: false},
+		{Input: "file.con", Expect: false},
+		{Input: "/", Expect: false},
+		{Input: "..", Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect bool) {
+		assert.Equal(t, expect, NewPath(input).IsReserved())
+	})
+}
+
+func TestPath_Validate(t *testing.T) {
+	type validateInput struct {
+		path   string
+		target OS
+	}
+
+	long := strings.Repeat("x", 256)
+
+	cases := []TestCase[validateInput, int]{
+		{Input: validateInput{"/home/user/file.txt", OSLinux}, Expect: 0},
+		{Input: validateInput{"dir/a:b?*.txt", OSLinux}, Expect: 0},
+		{Input: validateInput{"dir/a:b?*.txt", OSWindows}, Expect: 1},
+		{Input: validateInput{"C:/Users/file.txt", OSWindows}, Expect: 0},
+		{Input: validateInput{"dir/con.txt/nul", OSWindows}, Expect: 2},
+		{Input: validateInput{"dir./dir /file", OSWindows}, Expect: 2},
+		{Input: validateInput{"dir./dir /file", OSDarwin}, Expect: 0},
+		{Input: validateInput{"../file\x01", OSWindows}, Expect: 1},
+		{Input: validateInput{"dir/" + long, OSLinux}, Expect: 1},
+		{Input: validateInput{"dir/" + strings.Repeat("ä", 128), OSLinux}, Expect: 1},
+		{Input: validateInput{"dir/" + strings.Repeat("ä", 128), OSWindows}, Expect: 0},
+		{Input: validateInput{"nul\x00byte", OSLinux}, Expect: 1},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q %s]", testCase.Input.path[:min(len(testCase.Input.path), 30)], testCase.Input.target)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input validateInput, expect int) {
+		err := NewPath(input.path).Validate(input.target)
+		if expect == 0 {
+			assert.NoError(t, err)
+			return
+		}
+
+		assert.Error(t, err)
+		assert.Len(t, strings.Split(err.Error(), "\n"), expect)
+	})
+
+	assert.Equal(t, OS(runtime.GOOS), CurrentOS())
+}
+
+func TestPath_CheckLengthLimits(t *testing.T) {
+	type limitInput struct {
+		path   string
+		target OS
+	}
+
+	// element returns a path element of the passed length
+	element := func(length int) string {
+		return strings.Repeat("x", length)
+	}
+
+	deep := strings.Repeat(element(100)+"/", 11)
+
+	cases := []TestCase[limitInput, bool]{
+		{Input: limitInput{"/home/user/file.txt", OSWindows}, Expect: true},
+		{Input: limitInput{"/" + element(255), OSLinux}, Expect: true},
+		{Input: limitInput{"/" + element(256), OSLinux}, Expect: false},
+		{Input: limitInput{"/" + strings.Repeat("ä", 128), OSLinux}, Expect: false},
+		{Input: limitInput{"/" + strings.Repeat("ä", 128), OSWindows}, Expect: true},
+		{Input: limitInput{"/" + element(200) + "/" + element(57), OSWindows}, Expect: true},
+		{Input: limitInput{"/" + element(200) + "/" + element(58), OSWindows}, Expect: false},
+		{Input: limitInput{deep, OSDarwin}, Expect: false},
+		{Input: limitInput{deep, OSLinux}, Expect: true},
+		{Input: limitInput{strings.Repeat(deep, 4), OSLinux}, Expect: false},
+		{Input: limitInput{`C:\` + strings.Repeat(element(100)+`\`, 5), OSWindows}, Expect: false},
+		{Input: limitInput{`\\?\C:\` + strings.Repeat(element(100)+`\`, 5), OSWindows}, Expect: true},
+		{Input: limitInput{`\\?\C:\` + strings.Repeat(element(100)+`\`, 400), OSWindows}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%d %s]", i, testCase.Input.target)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input limitInput, expect bool) {
+		err := NewPath(input.path).CheckLengthLimits(input.target)
+		assert.Equal(t, expect, err == nil, err)
+	})
+}
+
+func TestPath_AbsoluteAndRelative(t *testing.T) {
+	cases := []TestCase[*Path, bool]{
+		{Input: NewPath("."), Expect: false},
+		{Input: NewPath(".."), Expect: false},
+		{Input: NewPath("/"), Expect: true},
+		{Input: NewPath("c:"), Expect: false},
+		{Input: NewPath("c:/"), Expect: false},
+		{Input: NewPath("c://"), Expect: false},
+		{Input: NewPath("c:\\"), Expect: false},
+		{Input: NewPath("c:\\\\"), Expect: false},
+		{Input: NewPath("foo/bar"), Expect: false},
+		{Input: NewPath("/foo/bar.js"), Expect: true},
+		{Input: NewPath("bar.js"), Expect: false},
+		{Input: NewPath("../bar"), Expect: false},
+		{Input: NewPath("../../bar.js"), Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect bool) {
+		assert.Equal(t, expect, input.IsAbsolute())
+		assert.Equal(t, !expect, input.IsRelative())
+	})
+}
+
+func TestPath_RelativeTo(t *testing.T) {
+	cases := []TestCase[[]*Path, *Path]{
+		{Input: []*Path{NewPath("/a/b"), NewPath("/")}, Expect: NewPath("a/b")},
+		{Input: []*Path{NewPath("/a/b"), NewPath("/a")}, Expect: NewPath("b")},
+		{Input: []*Path{NewPath("a/b"), NewPath("a")}, Expect: NewPath("b")},
+		{Input: []*Path{NewPath("a/b/d"), NewPath("a/b/c")}, Expect: NewPath("../d")},
+		{Input: []*Path{NewPath("/b"), NewPath("/a")}, Expect: NewPath("../b")},
+		{Input: []*Path{NewPath("/b/d"), NewPath("/a/c")}, Expect: NewPath("../../b/d")},
+		{Input: []*Path{NewPath("/"), NewPath("/a/b")}, Expect: NewPath("../..")},
+		{Input: []*Path{NewPath(""), NewPath("/a/b")}, Error: true},
+		{Input: []*Path{NewPath("../"), NewPath("/a/b")}, Error: true},
+		{Input: []*Path{NewPath("../b"), NewPath("a/b")}, Expect: NewPath("../../../b")},
+		{Input: []*Path{NewPath("a/b\\ whitespace/c"), NewPath("a/d")}, Expect: NewPath("../b whitespace/c")},
+	}
+
+	for i := range cases {
+		cases[i].Name = fmt.Sprintf("[%d]", i+1)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []*Path, expect *Path, error bool) {
+		assert.Equal(t, len(input), 2)
+
+		basePath := input[0]
+		otherPath := input[1]
+		relativePath, err := basePath.RelativeTo(otherPath)
+
+		assert.Equal(t, error, err != nil)
+		if !error {
+			assert.Equal(t, expect, relativePath)
+		}
+	})
+}
+
+func TestPath_IsWithin(t *testing.T) {
+	cases := []TestCase[[]*Path, bool]{
+		{Input: []*Path{NewPath("/a/b"), NewPath("/a")}, Expect: true},
+		{Input: []*Path{NewPath("/a"), NewPath("/a")}, Expect: true},
+		{Input: []*Path{NewPath("/a/b/../c"), NewPath("/a")}, Expect: true},
+		{Input: []*Path{NewPath("/a/../b"), NewPath("/a")}, Expect: false},
+		{Input: []*Path{NewPath("/ab"), NewPath("/a")}, Expect: false},
+		{Input: []*Path{NewPath("/a"), NewPath("/a/b")}, Expect: false},
+		{Input: []*Path{NewPath("/a"), NewPath("/")}, Expect: true},
+		{Input: []*Path{NewPath("a/b"), NewPath("a")}, Expect: true},
+		{Input: []*Path{NewPath("a"), NewPath("")}, Expect: true},
+		{Input: []*Path{NewPath("../a"), NewPath("")}, Expect: false},
+		{Input: []*Path{NewPath("../a/b"), NewPath("../a")}, Expect: true},
+		{Input: []*Path{NewPath("a"), NewPath("/a")}, Expect: false},
+		{Input: []*Path{NewPath("/a"), NewPath("a")}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []*Path, expect bool) {
+		assert.Equal(t, expect, input[0].IsWithin(input[1]))
+	})
+
+	t.Run("IsWithinFS", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symbolic links require privileges on windows")
+		}
+
+		tempPath := NewPath(t.TempDir())
+		base := tempPath.JoinStrings("base")
+		assert.NoError(t, base.JoinStrings("dir").MkdirAll(0755))
+		assert.NoError(t, os.Symlink(tempPath.String(), base.JoinStrings("escape").String()))
+		assert.NoError(t, os.Symlink("dir", base.JoinStrings("inside").String()))
+		assert.NoError(t, os.Symlink(base.String(), tempPath.JoinStrings("base-link").String()))
+
+		fsCases := []TestCase[*Path, bool]{
+			{Input: base.JoinStrings("dir", "file"), Expect: true},
+			{Input: base.JoinStrings("inside", "missing", "file"), Expect: true},
+			{Input: base.JoinStrings("escape"), Expect: false},
+			{Input: base.JoinStrings("escape", "base", "dir"), Expect: true},
+			{Input: base.JoinStrings("escape", "other"), Expect: false},
+			{Input: tempPath.JoinStrings("base-link", "dir"), Expect: true},
+		}
+
+		for i, testCase := range fsCases {
+			fsCases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+		}
+
+		runForResults(t, fsCases, func(t *testing.T, input *Path, expect bool) {
+			// the lexical check cannot detect escaping symbolic links
+			assert.Equal(t, input.path != tempPath.JoinStrings("base-link", "dir").path, input.IsWithin(base))
+
+			within, err := input.IsWithinFS(base)
+			assert.NoError(t, err)
+			assert.Equal(t, expect, within)
+		})
+	})
+}
+
+func TestPath_IsChildOf(t *testing.T) {
+	// expectations: IsChildOf, IsParentOf, IsDescendantOf, IsAncestorOf
+	cases := []TestCase[[]*Path, []bool]{
+		{Input: []*Path{NewPath("/a/b"), NewPath("/a")}, Expect: []bool{true, false, true, false}},
+		{Input: []*Path{NewPath("/a/b/c"), NewPath("/a")}, Expect: []bool{false, false, true, false}},
+		{Input: []*Path{NewPath("/a"), NewPath("/a/b")}, Expect: []bool{false, true, false, true}},
+		{Input: []*Path{NewPath("/a"), NewPath("/a/b/c")}, Expect: []bool{false, false, false, true}},
+		{Input: []*Path{NewPath("/a"), NewPath("/a")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("/"), NewPath("/")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("/a"), NewPath("/")}, Expect: []bool{true, false, true, false}},
+		{Input: []*Path{NewPath("/ab"), NewPath("/a")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("a"), NewPath(".")}, Expect: []bool{true, false, true, false}},
+		{Input: []*Path{NewPath("a"), NewPath("/")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath(".."), NewPath(".")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("."), NewPath("..")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("../.."), NewPath("..")}, Expect: []bool{false, false, false, false}},
+		{Input: []*Path{NewPath("../a"), NewPath("..")}, Expect: []bool{true, false, true, false}},
+		{Input: []*Path{NewPath("a/b"), NewPath("a")}, Expect: []bool{true, false, true, false}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []*Path, expect []bool) {
+		path, other := input[0], input[1]
+		assert.Equal(t, expect, []bool{path.IsChildOf(other), path.IsParentOf(other), path.IsDescendantOf(other), path.IsAncestorOf(other)})
+	})
+}
+
+func TestCommonPath(t *testing.T) {
+	cases := []TestCase[[]*Path, *Path]{
+		{Input: []*Path{NewPath("/a/b/c")}, Expect: NewPath("/a/b/c")},
+		{Input: []*Path{NewPath("/a/b/c"), NewPath("/a/b/d")}, Expect: NewPath("/a/b")},
+		{Input: []*Path{NewPath("/a/bc"), NewPath("/a/b")}, Expect: NewPath("/a")},
+		{Input: []*Path{NewPath("/a/b"), NewPath("/a/b/c"), NewPath("/a/d")}, Expect: NewPath("/a")},
+		{Input: []*Path{NewPath("/a"), NewPath("/b")}, Expect: NewPath("/")},
+		{Input: []*Path{NewPath("/"), NewPath("/a")}, Expect: NewPath("/")},
+		{Input: []*Path{NewPath("a/b"), NewPath("a/c")}, Expect: NewPath("a")},
+		{Input: []*Path{NewPath("a"), NewPath("b")}, Expect: NewPath(".")},
+		{Input: []*Path{NewPath("../a"), NewPath("../b")}, Expect: NewPath("..")},
+		{Input: []*Path{NewPath("a"), NewPath("")}, Expect: NewPath(".")},
+		{Input: []*Path{NewPath("/a"), NewPath("a")}, Error: true},
+		{Input: []*Path{}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []*Path, expect *Path, error bool) {
+		common, err := CommonPath(input...)
+		assert.Equal(t, error, err != nil)
+		assert.Equal(t, expect, common)
+	})
+}
+
+func TestPath_RelativeToWith(t *testing.T) {
+	type relInput struct {
+		path  *Path
+		other *Path
+		opts  RelOptions
+	}
+
+	cases := []TestCase[relInput, *Path]{
+		{Input: relInput{NewPath("/a/b"), NewPath("/a"), RelOptions{}}, Expect: NewPath("b")},
+		{Input: relInput{NewPath("/a"), NewPath("/a"), RelOptions{}}, Expect: NewPath(".")},
+		{Input: relInput{NewPath("a/b/c"), NewPath("a"), RelOptions{}}, Expect: NewPath("b/c")},
+		{Input: relInput{NewPath("/b"), NewPath("/a"), RelOptions{}}, Error: true},
+		{Input: relInput{NewPath("/b"), NewPath("/a"), RelOptions{WalkUp: true}}, Expect: NewPath("../b")},
+		{Input: relInput{NewPath("/"), NewPath("/a/b"), RelOptions{WalkUp: true}}, Expect: NewPath("../..")},
+		{Input: relInput{NewPath("a"), NewPath("/a"), RelOptions{WalkUp: true}}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s %s %v]", testCase.Input.path, testCase.Input.other, testCase.Input.opts)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input relInput, expect *Path, error bool) {
+		relativePath, err := input.path.RelativeToWith(input.other, input.opts)
+		assert.Equal(t, error, err != nil)
+		assert.Equal(t, expect, relativePath)
+	})
+}
+
+func TestPath_Absolute(t *testing.T) {
+	wdPath, err := NewCwd()
+	assert.NoError(t, err)
+
+	cases := []TestCase[*Path, *Path]{
+		{Input: NewPath("."), Expect: wdPath.JoinStrings(".")},
+		{Input: NewPath("foo"), Expect: wdPath.JoinStrings("foo")},
+		{Input: NewPath("foo/bar"), Expect: wdPath.JoinStrings("foo/bar")},
+		{Input: NewPath("/"), Expect: NewPath("/")},
+		{Input: NewPath("/foo"), Expect: NewPath("/foo")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect *Path) {
+		absolutePath, err := input.Absolute()
+		assert.NoError(t, err)
+
+		assert.Equal(t, expect, absolutePath)
+	})
+}
+
+func TestPath_AbsoluteTo(t *testing.T) {
+	cases := []TestCase[[]*Path, *Path]{
+		{Input: []*Path{NewPath("."), NewPath(".")}, Error: true},
+		{Input: []*Path{NewPath("/"), NewPath(".")}, Expect: NewPath("/")},
+		{Input: []*Path{NewPath("."), NewPath("/foo")}, Expect: NewPath("/foo")},
+		{Input: []*Path{NewPath("hello"), NewPath("/foo")}, Expect: NewPath("/foo/hello")},
+		{Input: []*Path{NewPath("../hello"), NewPath("/foo")}, Expect: NewPath("/hello")},
+		{Input: []*Path{NewPath("hello/bar"), NewPath("/foo")}, Expect: NewPath("/foo/hello/bar")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []*Path, expect *Path, error bool) {
+		assert.Equal(t, len(input), 2)
+
+		base := input[0]
+		other := input[1]
+		absolutePath, err := base.AbsoluteTo(other)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, absolutePath)
+		}
+	})
+}
+
+func TestPath_Resolve(t *testing.T) {
+	// temporary directory
+	tempPath := NewPath(t.TempDir()) // creates temporary directory at /var
+
+	// temporary file paths
+	originalFile := "originalFile"
+	symlinkFile := "symlinkedFile"
+
+	originalFilePath := NewPath("/private").JoinStrings(tempPath.String(), originalFile) // /var is actually a symlink to /private/var
+	symlinkFilePath := tempPath.JoinStrings(symlinkFile)
+
+	// create original file
+	file, err := os.OpenFile(originalFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
+	assert.NoError(t, err)
+
+	err = file.Close()
+	assert.NoError(t, err)
+
+	// create symlink
+	err = os.Symlink(originalFilePath.String(), symlinkFilePath.String())
+	assert.NoError(t, err)
+
+	cases := []TestCase[*Path, *Path]{
+		{Input: NewPath("."), Expect: NewPath(".")},
+		{Input: NewPath("./foo"), Error: true},
+		{Input: NewPath("/"), Expect: NewPath("/")},
+		{Input: originalFilePath, Expect: originalFilePath},
+		{Input: symlinkFilePath, Expect: originalFilePath},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input *Path, expect *Path, error bool) {
+		resolvedPath, err := input.Resolve()
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, resolvedPath)
+		}
+	})
+}
+
+func TestPath_Joins(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"/", "."}, Expect: NewPath("/")},
+		{Input: []string{"/", "foo"}, Expect: NewPath("/foo")},
+		{Input: []string{"/", "../"}, Expect: NewPath("/")},
+		{Input: []string{"/", "../b"}, Expect: NewPath("/b")},
+		{Input: []string{"a", "b"}, Expect: NewPath("a/b")},
+		{Input: []string{"a", "../b"}, Expect: NewPath("b")},
+		{Input: []string{"../a", "../b"}, Expect: NewPath("../b")},
+		{Input: []string{"../a", "../../b"}, Expect: NewPath("../../b")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.True(t, len(input) > 0)
+
+		basePath := NewPath(input[0])
+		joinedStrPath := basePath.JoinStrings(input[1:]...)
+
+		var strCvtPath []*Path = nil
+		for _, pathStr := range input[1:] {
+			strCvtPath = append(strCvtPath, NewPath(pathStr))
+		}
+		joinedPathsPath := basePath.Join(strCvtPath...)
+
+		assert.Equal(t, expect, joinedStrPath)
+		assert.Equal(t, expect, joinedPathsPath)
+	})
+}
+
+func TestPath_Iterdir(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, tempPath.JoinStrings("empty").Mkdir(0755))
+	assert.NoError(t, tempPath.JoinStrings("dir", "nested").MkdirAll(0755))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("dir", "b").String(), nil, 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("dir", "a b").String(), nil, 0644))
+
+	cases := []TestCase[string, []string]{
+		{Input: "empty", Expect: []string{}},
+		{Input: "dir", Expect: []string{"a b", "b", "nested"}},
+		{Input: "dir/b", Error: true},
+		{Input: "missing", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect []string, error bool) {
+		dir := tempPath.JoinStrings(input)
+
+		children, err := dir.Iterdir()
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			expectedChildren := make([]*Path, len(expect))
+			for i, name := range expect {
+				expectedChildren[i] = dir.JoinStrings(name)
+			}
+
+			assert.Equal(t, expectedChildren, children)
+		}
+	})
+}
+
+func TestPath_Walk(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, tempPath.JoinStrings("a", "b").MkdirAll(0755))
+	assert.NoError(t, tempPath.JoinStrings("skipped", "c").MkdirAll(0755))
+	for _, name := range []string{"file", "a/file", "a/b/file", "skipped/c/file"} {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), nil, 0644))
+	}
+
+	skipDir := func(p *Path, d fs.DirEntry) bool {
+		return d.Name() == "skipped"
+	}
+
+	// walk collects all visited paths relative to the temporary directory
+	walk := func(t *testing.T, root *Path, fn func(p *Path) error, opts WalkOptions) ([]string, error) {
+		var visited []string
+		err := root.Walk(func(p *Path, d fs.DirEntry) error {
+			rel, err := p.RelativeTo(tempPath)
+			assert.NoError(t, err)
+			visited = append(visited, rel.ToPosix())
+			return fn(p)
+		}, opts)
+
+		return visited, err
+	}
+
+	noop := func(p *Path) error { return nil }
+	errFile := func(p *Path) error {
+		if p.Base() == "file" {
+			return errors.New(p.String())
+		}
+		return nil
+	}
+
+	t.Run("all", func(t *testing.T) {
+		visited, err := walk(t, tempPath, noop, WalkOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{".", "a", "a/b", "a/b/file", "a/file", "file", "skipped", "skipped/c", "skipped/c/file"}, visited)
+	})
+
+	t.Run("skip directories", func(t *testing.T) {
+		visited, err := walk(t, tempPath, noop, WalkOptions{SkipDir: skipDir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{".", "a", "a/b", "a/b/file", "a/file", "file"}, visited)
+	})
+
+	t.Run("skip via callback", func(t *testing.T) {
+		visited, err := walk(t, tempPath, func(p *Path) error {
+			if p.Base() == "a" {
+				return fs.SkipDir
+			}
+			return nil
+		}, WalkOptions{SkipDir: skipDir})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{".", "a", "file"}, visited)
+	})
+
+	t.Run("abort on error", func(t *testing.T) {
+		visited, err := walk(t, tempPath, errFile, WalkOptions{})
+		assert.Error(t, err)
+		assert.Equal(t, []string{".", "a", "a/b", "a/b/file"}, visited)
+	})
+
+	t.Run("collect errors", func(t *testing.T) {
+		visited, err := walk(t, tempPath, errFile, WalkOptions{SkipDir: skipDir, CollectErrors: true})
+		assert.Error(t, err)
+		assert.Len(t, visited, 6)
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		_, err := walk(t, tempPath.JoinStrings("missing"), noop, WalkOptions{})
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPath_WalkSeq(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, tempPath.JoinStrings("a", "b").MkdirAll(0755))
+	for _, name := range []string{"file", "a/file", "a/b/file"} {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), nil, 0644))
+	}
+
+	t.Run("all", func(t *testing.T) {
+		var visited []string
+		for p, err := range tempPath.WalkSeq() {
+			assert.NoError(t, err)
+
+			rel, err := p.RelativeTo(tempPath)
+			assert.NoError(t, err)
+			visited = append(visited, rel.ToPosix())
+		}
+
+		assert.Equal(t, []string{".", "a", "a/b", "a/b/file", "a/file", "file"}, visited)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		count := 0
+		for range tempPath.WalkSeq() {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		count := 0
+		for p, err := range tempPath.JoinStrings("missing").WalkSeq() {
+			count++
+			assert.ErrorIs(t, err, os.ErrNotExist)
+			assert.Equal(t, tempPath.JoinStrings("missing"), p)
+		}
+
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestPath_AsFS(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	assert.NoError(t, tempPath.JoinStrings("dir").Mkdir(0755))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("dir", "file.txt").String(), []byte("content"), 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("root.txt").String(), []byte{}, 0644))
+
+	fsys := tempPath.AsFS()
+
+	var walked []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		walked = append(walked, path)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".", "dir", "dir/file.txt", "root.txt"}, walked)
+
+	content, err := fs.ReadFile(fsys, "dir/file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	matches, err := fs.Glob(fsys, "*.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"root.txt"}, matches)
+
+	_, err = fs.ReadFile(fsys, "../escape")
+	assert.Error(t, err)
+
+	assert.NoError(t, fstest.TestFS(fsys, "dir", "dir/file.txt", "root.txt"))
+}
+
+func TestPathOn(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root.txt":           {Data: []byte("root")},
+		"dir/file.txt":       {Data: []byte("content")},
+		"dir/file.go":        {Data: []byte{}},
+		"dir/nested/deep.go": {Data: []byte{}},
+	}
+
+	t.Run("construction", func(t *testing.T) {
+		cases := []TestCase[string, string]{
+			{Input: "", Expect: "."},
+			{Input: ".", Expect: "."},
+			{Input: "/", Expect: "."},
+			{Input: "/dir/", Expect: "dir"},
+			{Input: "dir/../root.txt", Expect: "root.txt"},
+			{Input: "../escape", Expect: "escape"},
+		}
+
+		for i, testCase := range cases {
+			cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+		}
+
+		runForResults(t, cases, func(t *testing.T, input string, expect string) {
+			assert.Equal(t, expect, PathOn(fsys, input).String())
+		})
+	})
+
+	t.Run("queries", func(t *testing.T) {
+		dir := PathOn(fsys, "dir")
+		file := dir.JoinStrings("file.txt")
+
+		assert.True(t, dir.IsDir())
+		assert.False(t, dir.IsFile())
+		assert.True(t, file.IsFile())
+		assert.True(t, file.Exists())
+		assert.False(t, dir.JoinStrings("missing").Exists())
+		assert.Equal(t, "file.txt", file.Base())
+		assert.Equal(t, dir, file.Parent())
+		assert.Equal(t, ".", dir.Parent().String())
+
+		text, err := file.ReadText()
+		assert.NoError(t, err)
+		assert.Equal(t, "content", text)
+
+		_, err = dir.JoinStrings("missing").ReadText()
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+
+		children, err := dir.Iterdir()
+		assert.NoError(t, err)
+		assert.Equal(t, []*FsPath{dir.JoinStrings("file.go"), file, dir.JoinStrings("nested")}, children)
+	})
+
+	t.Run("Glob", func(t *testing.T) {
+		type globInput struct {
+			base    string
+			pattern string
+		}
+
+		cases := []TestCase[globInput, []string]{
+			{Input: globInput{"", "*.txt"}, Expect: []string{"root.txt"}},
+			{Input: globInput{"", "*/*.txt"}, Expect: []string{"dir/file.txt"}},
+			{Input: globInput{"", "**/*.go"}, Expect: []string{"dir/file.go", "dir/nested/deep.go"}},
+			{Input: globInput{"dir", "*"}, Expect: []string{"dir/file.go", "dir/file.txt", "dir/nested"}},
+			{Input: globInput{"dir", "**/deep.go"}, Expect: []string{"dir/nested/deep.go"}},
+			{Input: globInput{"dir", "*.md"}, Expect: nil},
+			{Input: globInput{"dir", "[z"}, Error: true},
+			{Input: globInput{"dir", ""}, Error: true},
+			{Input: globInput{"root.txt", "*"}, Error: true},
+		}
+
+		for i, testCase := range cases {
+			cases[i].Name = fmt.Sprintf("[%s %s]", testCase.Input.base, testCase.Input.pattern)
+		}
+
+		runForResultsE(t, cases, func(t *testing.T, input globInput, expect []string, error bool) {
+			matches, err := PathOn(fsys, input.base).Glob(input.pattern)
+			assert.Equal(t, error, err != nil)
+
+			var names []string
+			for _, match := range matches {
+				names = append(names, match.String())
+			}
+			assert.Equal(t, expect, names)
+		})
+	})
+}
+
+//go:embed testdata/embedded
+var embeddedTestData embed.FS
+
+func TestNewEmbeddedPath(t *testing.T) {
+	root := NewEmbeddedPath(embeddedTestData, "testdata/embedded")
+	assert.True(t, root.IsDir())
+
+	text, err := root.JoinStrings("hello.txt").ReadText()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", text)
+
+	t.Run("MaterializeTo", func(t *testing.T) {
+		dest := NewPath(t.TempDir()).JoinStrings("assets")
+		assert.NoError(t, root.MaterializeTo(dest))
+
+		// existing files are overwritten
+		assert.NoError(t, os.WriteFile(dest.JoinStrings("hello.txt").String(), []byte("changed"), 0644))
+		assert.NoError(t, root.MaterializeTo(dest))
+
+		for name, expect := range map[string]string{"hello.txt": "hello\n", filepath.Join("dir", "nested.txt"): "nested\n"} {
+			content, err := os.ReadFile(dest.JoinStrings(name).String())
+			assert.NoError(t, err)
+			assert.Equal(t, expect, string(content))
+		}
+
+		file := NewPath(t.TempDir()).JoinStrings("single.txt")
+		assert.NoError(t, root.JoinStrings("dir", "nested.txt").MaterializeTo(file))
+		content, err := os.ReadFile(file.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "nested\n", string(content))
+
+		assert.ErrorIs(t, root.JoinStrings("missing").MaterializeTo(dest), fs.ErrNotExist)
+	})
+}
+
+func TestPath_GlobContains(t *testing.T) {
+
+	// NOTICE:
+	// This test only tests the existence check mechanism and
+	// matched filepath string conversions
+
+	// temporary directory
+	tempPath := NewPath(t.TempDir()) // creates temporary directory at /var
+
+	// create a file
+	existingFile := "foo"
+	existingFilePath := tempPath.JoinStrings(existingFile)
+	file, err := os.OpenFile(existingFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
+	assert.NoError(t, err)
+
+	err = file.Close()
+	assert.NoError(t, err)
+
+	// create subdirectory
+	existingSubdirectory := "bar"
+	existingSubdirectoryPath := tempPath.JoinStrings(existingSubdirectory)
+	err = os.Mkdir(existingSubdirectoryPath.String(), 0777)
+	assert.NoError(t, err)
+
+	// create file in subdirectory
+	existingSubdirFile := "baz"
+	existingSubdirFilePath := existingSubdirectoryPath.JoinStrings(existingSubdirFile)
+	subdirFile, err := os.OpenFile(existingSubdirFilePath.String(), os.O_RDONLY|os.O_CREATE, 0666)
+	assert.NoError(t, err)
+
+	err = subdirFile.Close()
+	assert.NoError(t, err)
+
+	// test cases; the first string is the root path
+	// starting at the temporary directory, the second
+	// string is the pattern to search for
+
+	cases := []TestCase[[]string, int]{
+		{Input: []string{"", ""}, Error: true},
+		{Input: []string{"", "  "}, Error: true},
+		{Input: []string{"", "  \t"}, Error: true},
+		{Input: []string{"", " \t \n  "}, Error: true},
+		{Input: []string{"", "*"}, Expect: 2},
+		{Input: []string{"", "/*"}, Expect: 2},
+		{Input: []string{"", "**"}, Expect: 2},
+		{Input: []string{"", "*/*"}, Expect: 1},
+		{Input: []string{"", "bar/*"}, Expect: 1},
+		{Input: []string{"", "bar/bar"}, Expect: 0},
+		{Input: []string{"", "bar/baz"}, Expect: 1},
+		{Input: []string{"", "bar/*z"}, Expect: 1},
+		{Input: []string{"", "bat/*z"}, Expect: 0},
+		{Input: []string{"", "**/baz"}, Expect: 1},
+		{Input: []string{"", "**/*"}, Expect: 3},
+		{Input: []string{"", "**/**"}, Expect: 2},
+		{Input: []string{"", "**/ba*"}, Expect: 2},
+		{Input: []string{"", "**/bar/**"}, Expect: 1},
+		{Input: []string{"", "bar/**/baz"}, Expect: 1},
+		{Input: []string{"", "bar/**"}, Expect: 1},
+		{Input: []string{"", "**/foo/**"}, Expect: 0},
+		{Input: []string{"", "**/[z"}, Error: true},
+		{Input: []string{"bar", "**/baz"}, Expect: 1},
+		{Input: []string{"bar", "**"}, Expect: 1},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []string, expect int, error bool) {
+		assert.Len(t, input, 2)
+		assert.GreaterOrEqual(t, expect, 0)
+
+		path := tempPath.JoinStrings(input[0])
+		pattern := input[1]
+
+		matches, globErr := path.Glob(pattern)
+		contains, containsErr := path.Contains(pattern)
+		containsB := path.BContains(pattern)
+
+		assert.Equal(t, error, globErr != nil)
+		assert.Equal(t, error, containsErr != nil)
+
+		if !error {
+			assert.Len(t, matches, expect)
+			assert.Equal(t, expect != 0, contains)
+			assert.Equal(t, contains, containsB)
+		}
+	})
+}
+
+func TestPath_GlobSeq(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, tempPath.JoinStrings("a", "b").MkdirAll(0755))
+	for _, file := range []string{"main.go", "a/a.go", "a/b/b.go", "a/b/readme.md"} {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(file).String(), nil, 0644))
+	}
+
+	cases := []TestCase[string, []string]{
+		{Input: "*.go", Expect: []string{"main.go"}},
+		{Input: "**/*.go", Expect: []string{"a/a.go", "a/b/b.go", "main.go"}},
+		{Input: "a/*", Expect: []string{"a/a.go", "a/b"}},
+		{Input: "**/**/*.go", Expect: []string{"a/a.go", "a/b/b.go", "main.go"}},
+		{Input: "a/*/../*.go", Expect: []string{"a/a.go"}},
+		{Input: "*.txt", Expect: []string{}},
+		{Input: " ", Error: true},
+		{Input: "[z", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect []string, error bool) {
+		matches := []string{}
+		errCount := 0
+		for match, err := range tempPath.GlobSeq(input) {
+			if err != nil {
+				errCount++
+				continue
+			}
+
+			rel, err := match.RelativeTo(tempPath)
+			assert.NoError(t, err)
+			matches = append(matches, rel.ToPosix())
+		}
+
+		if error {
+			assert.Equal(t, 1, errCount)
+			assert.Empty(t, matches)
+		} else {
+			assert.Equal(t, 0, errCount)
+			assert.ElementsMatch(t, expect, matches)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		count := 0
+		for range tempPath.GlobSeq("**/*") {
+			count++
+			break
+		}
+
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		for match, err := range tempPath.JoinStrings("missing").GlobSeq("*") {
+			assert.Nil(t, match)
+			assert.Error(t, err)
+		}
+	})
+}
+
+func TestPath_GlobWith(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	for _, dir := range []string{"cmd", "vendor/lib", "testdata", "pkg/testdata", "linked"} {
+		assert.NoError(t, tempPath.JoinStrings(dir).MkdirAll(0755))
+	}
+	for _, file := range []string{"main.go", "cmd/cmd.go", "vendor/lib/lib.go", "testdata/data.go", "pkg/testdata/data.go", "linked/linked.go", "README.md"} {
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(file).String(), nil, 0644))
+	}
+	assert.NoError(t, os.Symlink("../linked", tempPath.JoinStrings("cmd", "link").String()))
+
+	// symlink cycle
+	assert.NoError(t, os.Symlink("..", tempPath.JoinStrings("linked", "cycle").String()))
+
+	cases := []TestCase[GlobOptions, []string]{
+		{Input: GlobOptions{Pattern: "*.go"}, Expect: []string{"main.go"}},
+		{Input: GlobOptions{Pattern: "**/*.go"}, Expect: []string{"cmd/cmd.go", "linked/linked.go", "main.go", "pkg/testdata/data.go", "testdata/data.go", "vendor/lib/lib.go"}},
+		{Input: GlobOptions{Pattern: "**/*.go", Exclude: []string{"vendor", "testdata"}}, Expect: []string{"cmd/cmd.go", "linked/linked.go", "main.go", "pkg/testdata/data.go"}},
+		{Input: GlobOptions{Pattern: "**/*.go", Exclude: []string{"vendor", "**/testdata", "linked"}}, Expect: []string{"cmd/cmd.go", "main.go"}},
+		{Input: GlobOptions{Pattern: "**/*.go", Exclude: []string{"**/*.go"}}, Expect: []string{}},
+		{Input: GlobOptions{Pattern: "cmd/**/*.go"}, Expect: []string{"cmd/cmd.go"}},
+		{Input: GlobOptions{Pattern: "cmd/**/*.go", FollowSymlinks: true}, Expect: []string{"cmd/cmd.go", "cmd/link/cycle/cmd/cmd.go", "cmd/link/cycle/linked/linked.go", "cmd/link/cycle/main.go", "cmd/link/cycle/pkg/testdata/data.go", "cmd/link/cycle/testdata/data.go", "cmd/link/cycle/vendor/lib/lib.go", "cmd/link/linked.go"}},
+		{Input: GlobOptions{Pattern: "cmd/**/*.go", FollowSymlinks: true, Exclude: []string{"**/cycle"}}, Expect: []string{"cmd/cmd.go", "cmd/link/linked.go"}},
+		{Input: GlobOptions{Pattern: "**/*.GO"}, Expect: []string{}},
+		{Input: GlobOptions{Pattern: "README.MD"}, Expect: []string{}},
+		{Input: GlobOptions{Pattern: "README.MD", CaseInsensitive: true}, Expect: []string{"README.md"}},
+		{Input: GlobOptions{Pattern: "readme.*", CaseInsensitive: true}, Expect: []string{"README.md"}},
+		{Input: GlobOptions{Pattern: "[q-s]EADME.md", CaseInsensitive: true}, Expect: []string{"README.md"}},
+		{Input: GlobOptions{Pattern: "[A-Z]eadme.MD", CaseInsensitive: true}, Expect: []string{"README.md"}},
+		{Input: GlobOptions{Pattern: "[^a-z]*", CaseInsensitive: true}, Expect: []string{}},
+		{Input: GlobOptions{Pattern: "**/*.GO", CaseInsensitive: true, Exclude: []string{"VENDOR", "**/TESTDATA", "linked"}}, Expect: []string{"cmd/cmd.go", "main.go"}},
+		{Input: GlobOptions{Pattern: "CMD/*.go", CaseInsensitive: true}, Expect: []string{"cmd/cmd.go"}},
+		{Input: GlobOptions{Pattern: ""}, Error: true},
+		{Input: GlobOptions{Pattern: "[z"}, Error: true},
+		{Input: GlobOptions{Pattern: "*", Exclude: []string{"[z"}}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input.Pattern)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input GlobOptions, expect []string, error bool) {
+		matches, err := tempPath.GlobWith(input)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			relMatches := make([]string, len(matches))
+			for i, match := range matches {
+				rel, err := match.RelativeTo(tempPath)
+				assert.NoError(t, err)
+				relMatches[i] = rel.ToPosix()
+			}
+
+			assert.ElementsMatch(t, expect, relMatches)
+		}
+	})
+}
+
+func TestMatchSegmentFold(t *testing.T) {
+	// expectations are consistent with EqualsFold for literal patterns
+	cases := []TestCase[[]string, bool]{
+		{Input: []string{"README.md", "readme.MD"}, Expect: true},
+		{Input: []string{"STRASSE", "straße"}, Expect: true},
+		{Input: []string{"straße", "STRASSE"}, Expect: true},
+		{Input: []string{"\u212Aelvin", "kelvin"}, Expect: true},
+		{Input: []string{"kelvin", "[\u212A]elvin"}, Expect: true},
+		{Input: []string{"Kelvin", "[a-z]elvin"}, Expect: true},
+		{Input: []string{"kelvin", "[A-Z]elvin"}, Expect: true},
+		{Input: []string{"kelvin", "[^A-Z]elvin"}, Expect: false},
+		{Input: []string{"1elvin", "[^A-Z]elvin"}, Expect: true},
+		{Input: []string{"straße", "stra?e"}, Expect: true},
+		{Input: []string{"straße", "stra[ß]e"}, Expect: true},
+		{Input: []string{"STRASSE", "stra??e"}, Expect: true},
+		{Input: []string{"ÄRGER.txt", "ärger.*"}, Expect: true},
+		{Input: []string{"main.go", "*.GO"}, Expect: true},
+		{Input: []string{"main.go", "*.rs"}, Expect: false},
+		{Input: []string{"main.go", "[z"}, Expect: false},
+		{Input: []string{"main.go", "[]"}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s %s]", testCase.Input[0], testCase.Input[1])
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
+		assert.Equal(t, expect, matchSegment(input[1], input[0], true))
+		if !strings.ContainsAny(input[1], "*?[") {
+			assert.Equal(t, expect, EqualsFold(input[0], input[1]))
+		}
+	})
+}
+
+func TestPath_FullMatch(t *testing.T) {
+	cases := []TestCase[[]string, bool]{
+		{Input: []string{"main.go", "*.go"}, Expect: true},
+		{Input: []string{"cmd/main.go", "*.go"}, Expect: false},
+		{Input: []string{"cmd/main.go", "*/*.go"}, Expect: true},
+		{Input: []string{"cmd/main.go", "**/*.go"}, Expect: true},
+		{Input: []string{"main.go", "**/*.go"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "**/*.go"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "a/**/main.go"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "a/**/b/**/main.go"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "b/**"}, Expect: false},
+		{Input: []string{"a/b/c/main.go", "a/**"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "**"}, Expect: true},
+		{Input: []string{"a/b/c/main.go", "**/b"}, Expect: false},
+		{Input: []string{"/a/main.go", "**/*.go"}, Expect: false},
+		{Input: []string{"/a/main.go", "/**/*.go"}, Expect: true},
+		{Input: []string{"/a/main.go", "/a/*.go"}, Expect: true},
+		{Input: []string{"a/main.go", "/a/*.go"}, Expect: false},
+		{Input: []string{"a/main.go", "a/*.[gt]o"}, Expect: true},
+		{Input: []string{"a/main.go", "a/[z"}, Error: true},
+		{Input: []string{"a/main.go", " "}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []string, expect bool, error bool) {
+		assert.Len(t, input, 2)
+
+		matched, err := NewPath(input[0]).FullMatch(input[1])
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, matched)
+		}
+	})
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	rules := []string{
+		"# comment",
+		"",
+		"*.log",
+		"!important.log",
+		"build/",
+		"/root.txt",
+		"docs/*.md",
+		"**/cache",
+		"vendor/**",
+		"\\#hash",
+		"trailing   ",
+		"a/**/z",
+	}
+
+	matcher, err := NewIgnoreMatcher(NewPath("/project"), rules)
+	assert.NoError(t, err)
+
+	// the input holds the path and whether it's a directory
+	cases := []TestCase[[]string, bool]{
+		{Input: []string{"/project/app.log", "file"}, Expect: true},
+		{Input: []string{"/project/sub/app.log", "file"}, Expect: true},
+		{Input: []string{"/project/important.log", "file"}, Expect: false},
+		{Input: []string{"/project/app.go", "file"}, Expect: false},
+		{Input: []string{"/project/build", "dir"}, Expect: true},
+		{Input: []string{"/project/build", "file"}, Expect: false},
+		{Input: []string{"/project/sub/build", "dir"}, Expect: true},
+		{Input: []string{"/project/build/output", "file"}, Expect: true},
+		{Input: []string{"/project/root.txt", "file"}, Expect: true},
+		{Input: []string{"/project/sub/root.txt", "file"}, Expect: false},
+		{Input: []string{"/project/docs/readme.md", "file"}, Expect: true},
+		{Input: []string{"/project/docs/sub/readme.md", "file"}, Expect: false},
+		{Input: []string{"/project/x/y/cache", "dir"}, Expect: true},
+		{Input: []string{"/project/vendor", "dir"}, Expect: false},
+		{Input: []string{"/project/vendor/lib.go", "file"}, Expect: true},
+		{Input: []string{"/project/#hash", "file"}, Expect: true},
+		{Input: []string{"/project/trailing", "file"}, Expect: true},
+		{Input: []string{"/project/a/z", "file"}, Expect: true},
+		{Input: []string{"/project/a/b/c/z", "file"}, Expect: true},
+		{Input: []string{"/project", "dir"}, Expect: false},
+		{Input: []string{"/other/app.log", "file"}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
+		assert.Len(t, input, 2)
+		assert.Equal(t, expect, matcher.Match(NewPath(input[0]), input[1] == "dir"))
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := NewIgnoreMatcher(NewPath("."), []string{"[z"})
+		assert.Error(t, err)
+	})
+
+	t.Run("file and walk", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+
+		assert.NoError(t, tempPath.JoinStrings("build").Mkdir(0755))
+		for _, name := range []string{".gitignore", "app.go", "app.log", "build/output"} {
+			assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), nil, 0644))
+		}
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(".gitignore").String(), []byte("*.log\r\nbuild/\r\n"), 0644))
+
+		matcher, err := NewIgnoreMatcherFromFile(tempPath.JoinStrings(".gitignore"))
+		assert.NoError(t, err)
+
+		assert.True(t, tempPath.JoinStrings("build").IsIgnored(matcher))
+		assert.True(t, tempPath.JoinStrings("app.log").IsIgnored(matcher))
+		assert.False(t, tempPath.JoinStrings("app.go").IsIgnored(matcher))
+
+		var visited []string
+		err = tempPath.Walk(func(p *Path, d fs.DirEntry) error {
+			visited = append(visited, p.Base())
+			return nil
+		}, WalkOptions{Ignore: matcher})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{tempPath.Base(), ".gitignore", "app.go"}, visited)
+	})
+}
+
+func TestPath_CaseSensitivity(t *testing.T) {
+	// NOTICE:
+	// This function is difficult to test, as this is dependent on the underlying file system.
+	// This also means we cannot test os-dependent, because Linux-based operating systems
+	// support multiple file systems.
+
+	// This stability of this function will result by using and testing it.
+	// (which is very dirty)
+}
+
+func TestPath_Equals(t *testing.T) {
+	cases := []TestCase[[]string, bool]{
+		{Input: []string{"", ""}, Expect: true},
+		{Input: []string{"", "a"}, Expect: false},
+		{Input: []string{"foo", "foo"}, Expect: true},
+		{Input: []string{"   foo", "foo"}, Expect: true},
+		{Input: []string{"foo", "Foo"}, Expect: false},
+		{Input: []string{"./foo", "foo"}, Expect: true},
+		{Input: []string{"./foo", "\tfoo"}, Expect: true},
+		{Input: []string{"./foo", "/foo"}, Expect: false},
+		{Input: []string{"/foo", "/foo"}, Expect: true},
+		{Input: []string{"/foo", "/foo\n"}, Expect: true},
+		{Input: []string{"/foo", "/Foo"}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
+		assert.Len(t, input, 2)
+
+		basePath := NewPath(input[0])
+		pathEquals := basePath.Equals(NewPath(input[1]))
+		stringEquals := basePath.EqualsString(input[1])
+
+		assert.Equal(t, expect, pathEquals)
+		assert.Equal(t, expect, stringEquals)
+	})
+}
+
+func TestPath_EqualsCi(t *testing.T) {
+	cases := []TestCase[[]string, bool]{
+		{Input: []string{"", ""}, Expect: true},
+		{Input: []string{"", "a"}, Expect: false},
+		{Input: []string{"foo", "foo"}, Expect: true},
+		{Input: []string{"   foo", "foo"}, Expect: true},
+		{Input: []string{"foo", "Foo"}, Expect: true},
+		{Input: []string{"./foo", "foo"}, Expect: true},
+		{Input: []string{"./foo", "\tfoo"}, Expect: true},
+		{Input: []string{"./foo", "/foo"}, Expect: false},
+		{Input: []string{"/foo", "/foo"}, Expect: true},
+		{Input: []string{"/foo", "/foo\n"}, Expect: true},
+		{Input: []string{"/foo", "/Foo"}, Expect: true},
+		{Input: []string{"/\u00c4rger", "/\u00e4RGER"}, Expect: true},
+		{Input: []string{"stra\u00dfe", "STRASSE"}, Expect: true},
+		{Input: []string{"\u03a3\u03bf\u03c6\u03bf\u03c2", "\u03c3\u03bf\u03c6\u03bf\u03c3"}, Expect: true},
+		{Input: []string{"\u212a", "k"}, Expect: true},
+		{Input: []string{"\u0130", "i"}, Expect: false},
+		{Input: []string{"\u0131", "I"}, Expect: false},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect bool) {
+		assert.Len(t, input, 2)
+
+		basePath := NewPath(input[0])
+		pathEquals := basePath.EqualsCi(NewPath(input[1]))
+		stringEquals := basePath.EqualsStringCi(input[1])
+
+		assert.Equal(t, expect, pathEquals)
+		assert.Equal(t, expect, stringEquals)
+		assert.Equal(t, expect, EqualsFold(input[0], input[1]))
+	})
+}
+
+func TestPath_EqualsFS(t *testing.T) {
+	// NOTICE:
+	// This is difficult to test, as it is depending on IsCaseSensitiveFs()
+}
+
+func TestNewPathE(t *testing.T) {
+	cases := []TestCase[string, *Path]{
+		{Input: "/foo/bar/", Expect: NewPath("/foo/bar")},
+		{Input: "föö", Expect: NewPath("föö")},
+		{Input: ".", Expect: NewPath(".")},
+		{Input: "", Error: true},
+		{Input: " \t\n", Error: true},
+		{Input: "foo\x00bar", Error: true},
+		{Input: "foo\xffbar", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect *Path, error bool) {
+		path, err := NewPathE(input)
+		assert.Equal(t, error, err != nil)
+		assert.Equal(t, expect, path)
+	})
+}
+
+func TestNewPathWithOptions(t *testing.T) {
+	type parseInput struct {
+		path string
+		opts ParseOptions
+	}
+
+	cases := []TestCase[parseInput, string]{
+		{Input: parseInput{"  /foo/bar/  ", ParseOptions{TrimSpace: true}}, Expect: "/foo/bar"},
+		{Input: parseInput{"/foo/bar ", ParseOptions{}}, Expect: "/foo/bar "},
+		{Input: parseInput{"/foo/../bar", ParseOptions{}}, Expect: "/bar"},
+		{Input: parseInput{"/cafe\u0301", ParseOptions{}}, Expect: "/cafe\u0301"},
+		{Input: parseInput{"/cafe\u0301", ParseOptions{Normalization: NormalizationNFC}}, Expect: "/caf\u00e9"},
+		{Input: parseInput{"/caf\u00e9", ParseOptions{Normalization: NormalizationNFD}}, Expect: "/cafe\u0301"},
+	}
+
+	if runtime.GOOS != "windows" {
+		cases = append(cases,
+			TestCase[parseInput, string]{Input: parseInput{`/with\ whitespace`, ParseOptions{UnescapeWhitespace: true}}, Expect: "/with whitespace"},
+			TestCase[parseInput, string]{Input: parseInput{`/with\ whitespace`, ParseOptions{}}, Expect: `/with\ whitespace`},
+			TestCase[parseInput, string]{Input: parseInput{`foo\bar`, ParseOptions{BackslashSeparators: true}}, Expect: "foo/bar"},
+			TestCase[parseInput, string]{Input: parseInput{`foo\bar`, ParseOptions{}}, Expect: `foo\bar`},
+			TestCase[parseInput, string]{Input: parseInput{`\\server\share`, ParseOptions{BackslashSeparators: true}}, Expect: "/server/share"},
+			TestCase[parseInput, string]{Input: parseInput{`/with\ white\space`, DefaultParseOptions()}, Expect: "/with white/space"},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q %v]", testCase.Input.path, testCase.Input.opts)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input parseInput, expect string) {
+		assert.Equal(t, expect, NewPathWithOptions(input.path, input.opts).path)
+		if input.opts == DefaultParseOptions() {
+			assert.Equal(t, NewPath(input.path), NewPathWithOptions(input.path, input.opts))
+		}
+	})
+}
+
+func TestPath_ToPosix(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath(".."), Expect: ".."},
+		{Input: NewPath("/foo"), Expect: "/foo"},
+		{Input: NewPath("\\\\foo"), Expect: "/foo"},
+		{Input: NewPath("\\\\foo\\bar"), Expect: "/foo/bar"},
+		{Input: NewPath("\\\\foo\\\\bar"), Expect: "/foo/bar"},
+		{Input: NewPath("/foo/with\\ whitespace"), Expect: "/foo/with whitespace"},
+		{Input: NewPath("\\foo\\with\\ whitespace"), Expect: "/foo/with whitespace"},
+		{Input: NewPath("\\\\foo\\\\with\\ whitespace"), Expect: "/foo/with whitespace"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		toPosix := input.ToPosix()
+
+		assert.Equal(t, expect, toPosix)
+	})
+}
+
+func TestPath_ToWindows(t *testing.T) {
+	cases := []TestCase[*Path, [2]string]{
+		{Input: NewPath("."), Expect: [2]string{".", "."}},
+		{Input: NewPath("foo/bar"), Expect: [2]string{`foo\bar`, `foo\bar`}},
+		{Input: NewPath("/foo/bar"), Expect: [2]string{`\foo\bar`, `D:\foo\bar`}},
+		{Input: NewPath(filepath.Join("with whitespace", "file")), Expect: [2]string{`with whitespace\file`, `with whitespace\file`}},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = append(cases,
+			TestCase[*Path, [2]string]{Input: NewPath(`C:\foo`), Expect: [2]string{`C:\foo`, `C:\foo`}},
+			TestCase[*Path, [2]string]{Input: NewPath(`\\server\share\dir`), Expect: [2]string{`\\server\share\dir`, `\\server\share\dir`}},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect [2]string) {
+		assert.Equal(t, expect[0], input.ToWindows())
+		assert.Equal(t, expect[1], input.ToWindowsWithDrive("D:"))
+	})
+}
+
+func TestPath_AsURI(t *testing.T) {
+	cases := []TestCase[string, string]{
+		{Input: "foo/bar", Error: true},
+		{Input: ".", Error: true},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = append(cases,
+			TestCase[string, string]{Input: `C:\foo\bar`, Expect: "file:///C:/foo/bar"},
+			TestCase[string, string]{Input: `C:\with whitespace\%.txt`, Expect: "file:///C:/with%20whitespace/%25.txt"},
+			TestCase[string, string]{Input: `\\server\share\dir`, Expect: "file://server/share/dir"},
+			TestCase[string, string]{Input: `\\?\C:\foo`, Expect: "file:///C:/foo"},
+			TestCase[string, string]{Input: `\\?\UNC\server\share\dir`, Expect: "file://server/share/dir"},
+		)
+	} else {
+		cases = append(cases,
+			TestCase[string, string]{Input: "/", Expect: "file:///"},
+			TestCase[string, string]{Input: "/foo/bar", Expect: "file:///foo/bar"},
+			TestCase[string, string]{Input: "/with\\ whitespace/%#?.txt", Expect: "file:///with%20whitespace/%25%23%3F.txt"},
+			TestCase[string, string]{Input: "/caf\u00e9", Expect: "file:///caf%C3%A9"},
+			TestCase[string, string]{Input: `\\server\share\dir`, Expect: "file:///server/share/dir"},
+			TestCase[string, string]{Input: "//usr/lib", Expect: "file:///usr/lib"},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect string, error bool) {
+		uri, err := NewPath(input).AsURI()
+
+		assert.Equal(t, error, err != nil)
+		assert.Equal(t, expect, uri)
+	})
+}
+
+func TestNewPathFromURI(t *testing.T) {
+	cases := []TestCase[string, string]{
+		{Input: "http://example.com/foo", Error: true},
+		{Input: "file:foo/bar", Error: true},
+		{Input: "file://server", Error: true},
+		{Input: "file:///foo%00bar", Error: true},
+		{Input: "file:///foo%zz", Error: true},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = append(cases,
+			TestCase[string, string]{Input: "file:///C:/foo/bar", Expect: `C:\foo\bar`},
+			TestCase[string, string]{Input: "file:///c:/with%20whitespace/%25.txt", Expect: `c:\with whitespace\%.txt`},
+			TestCase[string, string]{Input: "file://localhost/C:/foo", Expect: `C:\foo`},
+			TestCase[string, string]{Input: "file://server/share/dir", Expect: `\\server\share\dir`},
+		)
+	} else {
+		cases = append(cases,
+			TestCase[string, string]{Input: "file:///", Expect: "/"},
+			TestCase[string, string]{Input: "file:///foo/bar/", Expect: "/foo/bar"},
+			TestCase[string, string]{Input: "file:///with%20whitespace/%25%23%3F.txt", Expect: "/with whitespace/%#?.txt"},
+			TestCase[string, string]{Input: "file:///caf%C3%A9", Expect: "/caf\u00e9"},
+			TestCase[string, string]{Input: "file://localhost/foo", Expect: "/foo"},
+			TestCase[string, string]{Input: "FILE:///foo", Expect: "/foo"},
+			TestCase[string, string]{Input: "file://server/share/dir", Error: true},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect string, error bool) {
+		path, err := NewPathFromURI(input)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, path.path)
+
+			uri, err := path.AsURI()
+			assert.NoError(t, err)
+			roundTrip, err := NewPathFromURI(uri)
+			assert.NoError(t, err)
+			assert.Equal(t, path, roundTrip)
+		}
+	})
+}
+
+func TestPath_AsURL(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath("foo/bar.html"), Expect: "foo/bar.html"},
+		{Input: NewPath(filepath.Join("with whitespace", "%#?.txt")), Expect: "with%20whitespace/%25%23%3F.txt"},
+		{Input: NewPath("caf\u00e9"), Expect: "caf%C3%A9"},
+	}
+
+	if runtime.GOOS != "windows" {
+		cases = append(cases, TestCase[*Path, string]{Input: NewPath("/foo/bar"), Expect: "/foo/bar"})
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.AsURL().String())
+	})
+}
+
+func TestPath_JoinURLPath(t *testing.T) {
+	root := NewPath(t.TempDir())
+
+	cases := []TestCase[string, *Path]{
+		{Input: "/", Expect: root},
+		{Input: "", Expect: root},
+		{Input: "/index.html", Expect: root.JoinStrings("index.html")},
+		{Input: "/docs/with%20whitespace/", Expect: &Path{path: filepath.Join(root.path, "docs", "with whitespace")}},
+		{Input: "/../../etc/passwd", Expect: root.JoinStrings("etc", "passwd")},
+		{Input: "/docs/..%2F..%2Fsecret", Expect: root.JoinStrings("secret")},
+		{Input: "/foo%00bar", Error: true},
+	}
+
+	if runtime.GOOS == "windows" {
+		cases = append(cases,
+			TestCase[string, *Path]{Input: "/..%5C..%5Csecret", Error: true},
+			TestCase[string, *Path]{Input: "/nul", Error: true},
+			TestCase[string, *Path]{Input: "/C:/secret", Error: true},
+		)
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect *Path, error bool) {
+		u, err := url.Parse(input)
+		assert.NoError(t, err)
+
+		joined, err := root.JoinURLPath(u)
+		assert.Equal(t, error, err != nil)
+		assert.Equal(t, expect, joined)
+	})
+}
+
+func TestPath_QuoteShell(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("."), Expect: "."},
+		{Input: NewPath("bar-1.2_3.txt"), Expect: "bar-1.2_3.txt"},
+		{Input: NewPath(filepath.Join("with whitespace", "file")), Expect: "'" + filepath.Join("with whitespace", "file") + "'"},
+		{Input: NewPath("it's"), Expect: `'it'\''s'`},
+		{Input: NewPath("$HOME"), Expect: "'$HOME'"},
+		{Input: NewPath("~user"), Expect: "'~user'"},
+		{Input: NewPath("*.txt"), Expect: "'*.txt'"},
+		{Input: NewPath("a;rm -rf b"), Expect: "'a;rm -rf b'"},
+		{Input: NewPath("caf\u00e9"), Expect: "'caf\u00e9'"},
+	}
+
+	if runtime.GOOS != "windows" {
+		cases = append(cases, TestCase[*Path, string]{Input: NewPath("/foo/bar"), Expect: "/foo/bar"})
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.QuoteShell())
+	})
+}
+
+func TestPath_QuotePowerShell(t *testing.T) {
+	cases := []TestCase[*Path, string]{
+		{Input: NewPath("foo"), Expect: "'foo'"},
+		{Input: NewPath(filepath.Join("with whitespace", "file")), Expect: "'" + filepath.Join("with whitespace", "file") + "'"},
+		{Input: NewPath("it's"), Expect: "'it''s'"},
+		{Input: NewPath("it\u2019s"), Expect: "'it\u2019\u2019s'"},
+		{Input: NewPath("$env:HOME"), Expect: "'$env:HOME'"},
+		{Input: NewPath("-file"), Expect: "'-file'"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect string) {
+		assert.Equal(t, expect, input.QuotePowerShell())
+	})
+}
+
+func TestPath_WithName(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"", "foo"}, Expect: NewPath("foo")},
+		{Input: []string{"/", "foo"}, Expect: NewPath("/foo")},
+		{Input: []string{"../", "foo"}, Expect: NewPath("foo")},
+		{Input: []string{"../..", "foo"}, Expect: NewPath("../foo")},
+		{Input: []string{"foo/bar", "foo"}, Expect: NewPath("foo/foo")},
+		{Input: []string{"/foo/bar", "foo"}, Expect: NewPath("/foo/foo")},
+		{Input: []string{"foo/file.txt", "bar.txt"}, Expect: NewPath("foo/bar.txt")},
+		{Input: []string{"foo/.txt", ".json"}, Expect: NewPath("foo/.json")},
+		{Input: []string{"/foo/.txt", ".json"}, Expect: NewPath("/foo/.json")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.True(t, len(input) == 2)
+
+		// call function and assert
+		path := NewPath(input[0])
+		changedName := path.WithName(input[1])
+
+		assert.Equal(t, expect, changedName)
+	})
+}
+
+func TestPath_WithSuffix(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"foo.txt", ".json"}, Expect: NewPath("foo.json")},
+		{Input: []string{"dir/foo.txt", "json"}, Expect: NewPath("dir/foo.json")},
+		{Input: []string{"/dir/foo.tar.gz", ".zip"}, Expect: NewPath("/dir/foo.tar.zip")},
+		{Input: []string{"foo.txt", ""}, Expect: NewPath("foo")},
+		{Input: []string{"foo", ".txt"}, Expect: NewPath("foo.txt")},
+		{Input: []string{".bashrc", ".bak"}, Expect: NewPath(".bashrc.bak")},
+		{Input: []string{"/", ".txt"}, Expect: NewPath("/")},
+		{Input: []string{".", ".txt"}, Expect: NewPath(".")},
+		{Input: []string{"..", ".txt"}, Expect: NewPath("..")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.Equal(t, expect, NewPath(input[0]).WithSuffix(input[1]))
+	})
+}
+
+func TestPath_WithStem(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"build/output.tar.gz", "release"}, Expect: NewPath("build/release.tar.gz")},
+		{Input: []string{"/foo.txt", "bar"}, Expect: NewPath("/bar.txt")},
+		{Input: []string{"foo", "bar"}, Expect: NewPath("bar")},
+		{Input: []string{".bashrc", ".zshrc"}, Expect: NewPath(".zshrc")},
+		{Input: []string{"foo.txt", ""}, Expect: NewPath(".txt")},
+		{Input: []string{"/", "bar"}, Expect: NewPath("/")},
+		{Input: []string{"..", "bar"}, Expect: NewPath("..")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.Equal(t, expect, NewPath(input[0]).WithStem(input[1]))
+	})
+}
+
+func TestPath_WithoutExtensions(t *testing.T) {
+	// expectations: WithoutExtension, WithoutExtensions
+	cases := []TestCase[*Path, []*Path]{
+		{Input: NewPath("foo.tar.gz"), Expect: []*Path{NewPath("foo.tar"), NewPath("foo")}},
+		{Input: NewPath("/dir/foo.txt"), Expect: []*Path{NewPath("/dir/foo"), NewPath("/dir/foo")}},
+		{Input: NewPath("dir.d/foo"), Expect: []*Path{NewPath("dir.d/foo"), NewPath("dir.d/foo")}},
+		{Input: NewPath(".bashrc"), Expect: []*Path{NewPath(".bashrc"), NewPath(".bashrc")}},
+		{Input: NewPath("/"), Expect: []*Path{NewPath("/"), NewPath("/")}},
+		{Input: NewPath(".."), Expect: []*Path{NewPath(".."), NewPath("..")}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []*Path) {
+		assert.Equal(t, expect, []*Path{input.WithoutExtension(), input.WithoutExtensions()})
+	})
+}
+
+func TestPath_AddSuffix(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"foo.tar", ".gz"}, Expect: NewPath("foo.tar.gz")},
+		{Input: []string{"/dir/foo", "txt"}, Expect: NewPath("/dir/foo.txt")},
+		{Input: []string{".bashrc", ".bak"}, Expect: NewPath(".bashrc.bak")},
+		{Input: []string{"foo.txt", ""}, Expect: NewPath("foo.txt")},
+		{Input: []string{"/", ".gz"}, Expect: NewPath("/")},
+		{Input: []string{".", ".gz"}, Expect: NewPath(".")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.Equal(t, expect, NewPath(input[0]).AddSuffix(input[1]))
+	})
+}
+
+func TestPath_WithSegments(t *testing.T) {
+	cases := []TestCase[[]string, *Path]{
+		{Input: []string{"/foo/bar", "baz", "qux"}, Expect: NewPath("/baz/qux")},
+		{Input: []string{"/foo/bar"}, Expect: NewPath("/")},
+		{Input: []string{"/", "baz/qux"}, Expect: NewPath("/baz/qux")},
+		{Input: []string{"foo/bar", "baz", "qux"}, Expect: NewPath("baz/qux")},
+		{Input: []string{"foo/bar", "..", "baz"}, Expect: NewPath("../baz")},
+		{Input: []string{"foo"}, Expect: NewPath(".")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect *Path) {
+		assert.Equal(t, expect, NewPath(input[0]).WithSegments(input[1:]...))
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	cases := []TestCase[string, string]{
+		{Input: "Hello World", Expect: "hello-world"},
+		{Input: "  Über Café!  ", Expect: "uber-cafe"},
+		{Input: "Straße & Smørrebrød", Expect: "strasse-smorrebrod"},
+		{Input: "été", Expect: "ete"},
+		{Input: "Release v1.2.3 (final)", Expect: "release-v1-2-3-final"},
+		{Input: "already-a-slug", Expect: "already-a-slug"},
+		{Input: "日本語 title", Expect: "title"},
+		{Input: "---", Expect: ""},
+		{Input: "", Expect: ""},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect string) {
+		assert.Equal(t, expect, Slugify(input))
+	})
+
+	t.Run("WithSlugifiedName", func(t *testing.T) {
+		pathCases := []TestCase[*Path, *Path]{
+			{Input: NewPath("posts/My First Post.MD"), Expect: NewPath("posts/my-first-post.md")},
+			{Input: NewPath("/Ünïcödé.tar.GZ"), Expect: NewPath("/unicode.tar.gz")},
+			{Input: NewPath("dir/日本語"), Expect: NewPath("dir/日本語")},
+			{Input: NewPath("/"), Expect: NewPath("/")},
+		}
+
+		for i, testCase := range pathCases {
+			pathCases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+		}
+
+		runForResults(t, pathCases, func(t *testing.T, input *Path, expect *Path) {
+			assert.Equal(t, expect, input.WithSlugifiedName())
+		})
+	})
+}
+
+func TestPath_Normalize(t *testing.T) {
+	type normalized struct {
+		nfc string
+		nfd string
+	}
+
+	cases := []TestCase[*Path, normalized]{
+		{Input: NewPath("foo/bar.txt"), Expect: normalized{"foo/bar.txt", "foo/bar.txt"}},
+		{Input: NewPath("caf\u00e9/r\u00e9sum\u00e9.txt"), Expect: normalized{"caf\u00e9/r\u00e9sum\u00e9.txt", "cafe\u0301/re\u0301sume\u0301.txt"}},
+		{Input: NewPath("cafe\u0301/re\u0301sume\u0301.txt"), Expect: normalized{"caf\u00e9/r\u00e9sum\u00e9.txt", "cafe\u0301/re\u0301sume\u0301.txt"}},
+		{Input: NewPath("\u212bngstr\u00f6m"), Expect: normalized{"\u00c5ngstr\u00f6m", "A\u030angstro\u0308m"}},
+		{Input: NewPath("q\u0307\u0323"), Expect: normalized{"q\u0323\u0307", "q\u0323\u0307"}},
+		{Input: NewPath("\ud55c\uae00"), Expect: normalized{"\ud55c\uae00", "\u1112\u1161\u11ab\u1100\u1173\u11af"}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%+q]", testCase.Input.path)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect normalized) {
+		nfc := input.NormalizeNFC()
+		nfd := input.NormalizeNFD()
+
+		assert.Equal(t, expect.nfc, nfc.path)
+		assert.Equal(t, expect.nfd, nfd.path)
+		assert.True(t, nfc.Equals(nfd.NormalizeNFC()))
+		assert.True(t, nfd.Equals(nfc.NormalizeNFD()))
+	})
+}
+
+func TestPath_NextAvailable(t *testing.T) {
+	tempDir := t.TempDir()
+	tempPath := NewPath(tempDir)
+
+	// String escapes whitespaces, so the files are created using plain strings
+	for _, name := range []string{"report.txt", "report (1).txt", "archive.tar.gz", ".bashrc", "dir"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(tempDir, name), []byte{}, 0644))
+	}
+
+	cases := []TestCase[string, string]{
+		{Input: "missing.txt", Expect: "missing.txt"},
+		{Input: "report.txt", Expect: "report (2).txt"},
+		{Input: "archive.tar.gz", Expect: "archive.tar (1).gz"},
+		{Input: ".bashrc", Expect: ".bashrc (1)"},
+		{Input: "dir", Expect: "dir (1)"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect string) {
+		path, err := tempPath.JoinStrings(input).NextAvailable()
+		assert.NoError(t, err)
+		assert.Equal(t, tempPath.JoinStrings(expect), path)
+	})
+}
+
+func TestPath_RotateBackups(t *testing.T) {
+	type rotateInput struct {
+		keep    int
+		pattern string
+	}
+
+	cases := []TestCase[rotateInput, map[string]string]{
+		{Input: rotateInput{3, ""}, Expect: map[string]string{"app.log.1": "current", "app.log.2": "first", "app.log.3": "second"}},
+		{Input: rotateInput{2, ".%d"}, Expect: map[string]string{"app.log.1": "current", "app.log.2": "first"}},
+		{Input: rotateInput{1, ""}, Expect: map[string]string{"app.log.1": "current"}},
+		{Input: rotateInput{0, ""}, Expect: map[string]string{}},
+		{Input: rotateInput{2, "-%d.bak"}, Expect: map[string]string{"app.log-1.bak": "current", "app.log.1": "first", "app.log.2": "second"}},
+		{Input: rotateInput{-1, ""}, Error: true},
+		{Input: rotateInput{2, ".bak"}, Error: true},
+		{Input: rotateInput{2, ".%d.%s"}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%v]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input rotateInput, expect map[string]string, error bool) {
+		tempPath := NewPath(t.TempDir())
+		files := map[string]string{"app.log": "current", "app.log.1": "first", "app.log.2": "second"}
+		for name, content := range files {
+			assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), []byte(content), 0644))
+		}
+
+		err := tempPath.JoinStrings("app.log").RotateBackups(input.keep, input.pattern)
+		assert.Equal(t, error, err != nil)
+		if error {
+			return
+		}
+
+		entries, err := os.ReadDir(tempPath.String())
+		assert.NoError(t, err)
+
+		result := make(map[string]string)
+		for _, entry := range entries {
+			content, err := os.ReadFile(tempPath.JoinStrings(entry.Name()).String())
+			assert.NoError(t, err)
+			result[entry.Name()] = string(content)
+		}
+
+		assert.Equal(t, expect, result)
+	})
+
+	t.Run("gaps", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		files := []string{"app.log", "app.log.1", "app.log.2", "app.log.5", "app.log.7", "app.log.07", "app.log.old"}
+		for _, name := range files {
+			assert.NoError(t, os.WriteFile(tempPath.JoinStrings(name).String(), []byte(name), 0644))
+		}
+
+		assert.NoError(t, tempPath.JoinStrings("app.log").RotateBackups(3, ""))
+
+		entries, err := os.ReadDir(tempPath.String())
+		assert.NoError(t, err)
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+
+		// backups above the gap are pruned, names not produced by the pattern are kept
+		assert.Equal(t, []string{"app.log.07", "app.log.1", "app.log.2", "app.log.3", "app.log.old"}, names)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		assert.NoError(t, tempPath.JoinStrings("app.log").RotateBackups(3, ""))
+		assert.False(t, tempPath.JoinStrings("app.log.1").Exists())
+	})
+}
+
+func TestPath_Mkdir(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	existingFilePath := tempPath.JoinStrings("file")
+	err := os.WriteFile(existingFilePath.String(), nil, 0644)
+	assert.NoError(t, err)
+
+	// the expected slice holds whether Mkdir, MkdirExistOk and MkdirAll fail
+	cases := []TestCase[*Path, []bool]{
+		{Input: tempPath.JoinStrings("new"), Expect: []bool{false, false, false}},
+		{Input: tempPath, Expect: []bool{true, false, false}},
+		{Input: tempPath.JoinStrings("nested", "dir"), Expect: []bool{true, true, false}},
+		{Input: existingFilePath, Expect: []bool{true, true, true}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect []bool) {
+		assert.Len(t, expect, 3)
+
+		funcs := []func(os.FileMode) error{input.Mkdir, input.MkdirExistOk, input.MkdirAll}
+		for i, mkdir := range funcs {
+			// remove directories created by a previous function
+			if !input.Equals(tempPath) && input.IsDir() {
+				assert.NoError(t, os.RemoveAll(tempPath.JoinStrings(input.Parts()[len(tempPath.Parts())]).String()))
+			}
+
+			err := mkdir(0755)
+			assert.Equal(t, expect[i], err != nil, "function %d", i)
+
+			if !expect[i] {
+				assert.True(t, input.IsDir())
+			}
+		}
+	})
+}
+
+func TestPath_UnlinkRmdir(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	// setup creates a file, a symlink, an empty and a non-empty directory
+	setup := func(t *testing.T) {
+		for _, name := range []string{"file", "dir", "empty", "link"} {
+			assert.NoError(t, os.RemoveAll(tempPath.JoinStrings(name).String()))
+		}
+
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings("file").String(), nil, 0644))
+		assert.NoError(t, os.Symlink("dir", tempPath.JoinStrings("link").String()))
+		assert.NoError(t, os.Mkdir(tempPath.JoinStrings("empty").String(), 0755))
+		assert.NoError(t, os.Mkdir(tempPath.JoinStrings("dir").String(), 0755))
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings("dir", "file").String(), nil, 0644))
+	}
+
+	// the expected slice holds whether Unlink(false), Unlink(true) and Rmdir fail
+	cases := []TestCase[string, []bool]{
+		{Input: "file", Expect: []bool{false, false, true}},
+		{Input: "link", Expect: []bool{false, false, true}},
+		{Input: "empty", Expect: []bool{true, true, false}},
+		{Input: "dir", Expect: []bool{true, true, true}},
+		{Input: "missing", Expect: []bool{true, false, true}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect []bool) {
+		assert.Len(t, expect, 3)
+		path := tempPath.JoinStrings(input)
+
+		funcs := []func() error{
+			func() error { return path.Unlink(false) },
+			func() error { return path.Unlink(true) },
+			path.Rmdir,
+		}
+
+		for i, remove := range funcs {
+			setup(t)
+
+			err := remove()
+			assert.Equal(t, expect[i], err != nil, "function %d", i)
+
+			if !expect[i] {
+				_, err = os.Lstat(path.String())
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			}
+		}
+
+		// symlink targets are never removed
+		assert.True(t, tempPath.JoinStrings("dir", "file").IsFile())
+	})
+}
+
+func TestPath_RemoveTree(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	home, err := NewHome()
+	assert.NoError(t, err)
+
+	cases := []TestCase[*Path, interface{}]{
+		{Input: &Path{}, Error: true},
+		{Input: NewPath(""), Error: true},
+		{Input: NewPath("."), Error: true},
+		{Input: NewPath("/"), Error: true},
+		{Input: NewPath("/.."), Error: true},
+		{Input: home, Error: true},
+		{Input: tempPath.JoinStrings("missing")},
+		{Input: tempPath.JoinStrings("tree")},
+		{Input: tempPath.JoinStrings("unconfirmed"), Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	for _, dir := range []string{"tree", "unconfirmed"} {
+		assert.NoError(t, tempPath.JoinStrings(dir, "nested").MkdirAll(0755))
+		assert.NoError(t, os.WriteFile(tempPath.JoinStrings(dir, "nested", "file").String(), nil, 0644))
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input *Path, expect interface{}, error bool) {
+		// confirm is never called for refused paths
+		confirm := func(p *Path) bool {
+			assert.True(t, p.IsAbsolute())
+			return p.Base() != "unconfirmed"
+		}
+
+		err := input.RemoveTree(confirm)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.False(t, input.Exists())
+		}
+	})
+
+	assert.True(t, tempPath.JoinStrings("unconfirmed", "nested", "file").IsFile())
+}
+
+func TestPath_RenameReplace(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	// the input holds source and target, the expected slice holds
+	// whether Rename and Replace fail
+	cases := []TestCase[[]string, []bool]{
+		{Input: []string{"source", "target"}, Expect: []bool{false, false}},
+		{Input: []string{"source", "existing"}, Expect: []bool{true, false}},
+		{Input: []string{"missing", "target"}, Expect: []bool{true, true}},
+		{Input: []string{"source", "missing/target"}, Expect: []bool{true, true}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input []string, expect []bool) {
+		assert.Len(t, input, 2)
+		assert.Len(t, expect, 2)
+
+		source := tempPath.JoinStrings(input[0])
+		target := tempPath.JoinStrings(input[1])
+
+		funcs := []func(*Path) (*Path, error){source.Rename, source.Replace}
+		for i, rename := range funcs {
+			assert.NoError(t, tempPath.JoinStrings("target").Unlink(true))
+			assert.NoError(t, os.WriteFile(tempPath.JoinStrings("source").String(), []byte("source"), 0644))
+			assert.NoError(t, os.WriteFile(tempPath.JoinStrings("existing").String(), []byte("existing"), 0644))
+
+			renamed, err := rename(target)
+			assert.Equal(t, expect[i], err != nil, "function %d", i)
+
+			if !expect[i] {
+				assert.Equal(t, target, renamed)
+				assert.False(t, source.Exists())
+
+				content, err := os.ReadFile(target.String())
+				assert.NoError(t, err)
+				assert.Equal(t, "source", string(content))
+			} else if input[1] == "existing" {
+				assert.ErrorIs(t, err, os.ErrExist)
+				assert.True(t, source.Exists())
+			}
+		}
+	})
+}
+
+func TestPath_Move(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	source := tempPath.JoinStrings("source")
+	assert.NoError(t, source.JoinStrings("nested").MkdirAll(0755))
+	assert.NoError(t, os.WriteFile(source.JoinStrings("nested", "file").String(), []byte("content"), 0600))
+	assert.NoError(t, os.Symlink("nested/file", source.JoinStrings("link").String()))
+	assert.NoError(t, os.Chmod(source.JoinStrings("nested").String(), 0500))
+	t.Cleanup(func() {
+		_ = filepath.WalkDir(tempPath.String(), func(path string, d os.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				_ = os.Chmod(path, 0755)
+			}
+			return nil
+		})
+	})
+
+	existing := tempPath.JoinStrings("existing")
+	assert.NoError(t, existing.Mkdir(0755))
+
+	// assertTree asserts that the tree was copied with modes and links
+	assertTree := func(t *testing.T, dest *Path) {
+		content, err := os.ReadFile(dest.JoinStrings("nested", "file").String())
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+
+		fileInfo, err := os.Stat(dest.JoinStrings("nested", "file").String())
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), fileInfo.Mode().Perm())
+
+		dirInfo, err := os.Stat(dest.JoinStrings("nested").String())
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0500), dirInfo.Mode().Perm())
+
+		linkTarget, err := os.Readlink(dest.JoinStrings("link").String())
+		assert.NoError(t, err)
+		assert.Equal(t, "nested/file", linkTarget)
+	}
+
+	t.Run("copy tree", func(t *testing.T) {
+		dest := tempPath.JoinStrings("copied")
+		assert.NoError(t, copyTree(source.String(), dest.String()))
+		assertTree(t, dest)
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		err := source.Move(existing)
+		assert.ErrorIs(t, err, os.ErrExist)
+		assert.True(t, source.IsDir())
+	})
+
+	t.Run("move", func(t *testing.T) {
+		dest := tempPath.JoinStrings("moved")
+		assert.NoError(t, source.Move(dest))
+		assert.False(t, source.Exists())
+		assertTree(t, dest)
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		assert.Error(t, source.Move(tempPath.JoinStrings("other")))
+	})
+}
+
+func TestPath_Open(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("with space")
+
+	t.Run("Open", func(t *testing.T) {
+		file, err := filePath.Open(os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		assert.NoError(t, err)
+
+		_, err = file.WriteString("content")
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+
+		_, err = filePath.Open(os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		assert.ErrorIs(t, err, os.ErrExist)
+	})
+
+	t.Run("OpenRead", func(t *testing.T) {
+		file, err := filePath.OpenRead()
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+		assert.NoError(t, file.Close())
+
+		_, err = tempPath.JoinStrings("missing").OpenRead()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPath_OpenBuffered(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("file")
+
+	t.Run("OpenBufferedWriter", func(t *testing.T) {
+		writer, err := filePath.OpenBufferedWriter()
+		assert.NoError(t, err)
+
+		_, err = writer.WriteString("first line\nsecond line\n")
+		assert.NoError(t, err)
+
+		// data is only written after flushing
+		content, err := os.ReadFile(filePath.String())
+		assert.NoError(t, err)
+		assert.Empty(t, content)
+
+		assert.NoError(t, writer.Close())
+
+		content, err = os.ReadFile(filePath.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "first line\nsecond line\n", string(content))
+
+		_, err = tempPath.JoinStrings("missing", "file").OpenBufferedWriter()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("OpenBufferedReader", func(t *testing.T) {
+		reader, err := filePath.OpenBufferedReader()
+		assert.NoError(t, err)
+
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "first line\n", line)
+		assert.NoError(t, reader.Close())
+
+		_, err = tempPath.JoinStrings("missing").OpenBufferedReader()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPath_EnsureFile(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	existingFilePath := tempPath.JoinStrings("existing")
+	err := os.WriteFile(existingFilePath.String(), []byte("existing"), 0644)
+	assert.NoError(t, err)
+
+	cases := []TestCase[*Path, []string]{
+		{Input: tempPath.JoinStrings("new"), Expect: []string{"true", "default"}},
+		{Input: tempPath.JoinStrings("nested", "dirs", "new"), Expect: []string{"true", "default"}},
+		{Input: existingFilePath, Expect: []string{"false", "existing"}},
+		{Input: tempPath, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input *Path, expect []string, error bool) {
+		created, err := input.EnsureFile([]byte("default"), 0644)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Len(t, expect, 2)
+			assert.Equal(t, expect[0], fmt.Sprint(created))
+
+			content, err := os.ReadFile(input.String())
+			assert.NoError(t, err)
+			assert.Equal(t, expect[1], string(content))
+
+			// a second call never creates the file again
+			created, err = input.EnsureFile([]byte("other"), 0644)
+			assert.NoError(t, err)
+			assert.False(t, created)
+		}
+	})
+
+	t.Run("dangling symlink", func(t *testing.T) {
+		linkPath := tempPath.JoinStrings("dangling")
+		assert.NoError(t, os.Symlink(tempPath.JoinStrings("missing").String(), linkPath.String()))
+
+		created, err := linkPath.EnsureFile([]byte("default"), 0644)
+		assert.Error(t, err)
+		assert.False(t, created)
+		assert.False(t, tempPath.JoinStrings("missing").Exists())
+	})
+}
+
+func TestPath_WriteIfChanged(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("file")
+	pastTime := time.Unix(0, 0)
+
+	cases := []TestCase[string, bool]{
+		{Name: "create", Input: "foo", Expect: true},
+		{Name: "unchanged", Input: "foo", Expect: false},
+		{Name: "changed", Input: "bar", Expect: true},
+		{Name: "empty", Input: "", Expect: true},
+		{Name: "unchanged empty", Input: "", Expect: false},
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect bool) {
+		if filePath.Exists() {
+			err := os.Chtimes(filePath.String(), pastTime, pastTime)
+			assert.NoError(t, err)
+		}
+
+		written, err := filePath.WriteIfChanged([]byte(input), 0644)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, written)
+
+		content, err := os.ReadFile(filePath.String())
+		assert.NoError(t, err)
+		assert.Equal(t, input, string(content))
+
+		info, err := os.Stat(filePath.String())
+		assert.NoError(t, err)
+		assert.Equal(t, !expect, info.ModTime().Equal(pastTime))
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		_, err := tempPath.WriteIfChanged([]byte("foo"), 0644)
+		assert.Error(t, err)
+	})
+}
+
+func TestPath_ReadLines(t *testing.T) {
+	longLine := strings.Repeat("x", 100000)
+
+	cases := []TestCase[string, []string]{
+		{Input: "", Expect: nil},
+		{Input: "one", Expect: []string{"one"}},
+		{Input: "one\n", Expect: []string{"one"}},
+		{Input: "one\ntwo", Expect: []string{"one", "two"}},
+		{Input: "one\r\ntwo\r\n", Expect: []string{"one", "two"}},
+		{Input: "\n\none\n\n", Expect: []string{"", "", "one", ""}},
+		{Input: "carriage\rreturn\n", Expect: []string{"carriage\rreturn"}},
+		{Input: longLine + "\nshort", Expect: []string{longLine, "short"}},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q]", testCase.Input[:min(len(testCase.Input), 20)])
+	}
+
+	runForResults(t, cases, func(t *testing.T, input string, expect []string) {
+		filePath := NewPath(t.TempDir()).JoinStrings("file")
+		assert.NoError(t, os.WriteFile(filePath.String(), []byte(input), 0644))
+
+		lines, err := filePath.ReadLines()
+		assert.NoError(t, err)
+		assert.Equal(t, expect, lines)
+	})
+
+	t.Run("early break", func(t *testing.T) {
+		filePath := NewPath(t.TempDir()).JoinStrings("file")
+		assert.NoError(t, os.WriteFile(filePath.String(), []byte("one\ntwo\nthree\n"), 0644))
+
+		var lines []string
+		for line, err := range filePath.Lines() {
+			assert.NoError(t, err)
+			lines = append(lines, line)
+			if len(lines) == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, []string{"one", "two"}, lines)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewPath(t.TempDir()).JoinStrings("missing").ReadLines()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestPath_WriteLines(t *testing.T) {
+	type writeLinesInput struct {
+		lines []string
+		opts  LineOptions
+	}
+
+	cases := []TestCase[writeLinesInput, string]{
+		{Input: writeLinesInput{nil, LineOptions{}}, Expect: ""},
+		{Input: writeLinesInput{[]string{"one", "two"}, LineOptions{}}, Expect: "one\ntwo\n"},
+		{Input: writeLinesInput{[]string{"one", "two"}, LineOptions{CRLF: true}}, Expect: "one\r\ntwo\r\n"},
+		{Input: writeLinesInput{[]string{"one", "two"}, LineOptions{OmitTrailingNewline: true}}, Expect: "one\ntwo"},
+		{Input: writeLinesInput{[]string{"one", ""}, LineOptions{CRLF: true, OmitTrailingNewline: true}}, Expect: "one\r\n"},
+		{Input: writeLinesInput{[]string{""}, LineOptions{}}, Expect: "\n"},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%q %v]", testCase.Input.lines, testCase.Input.opts)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input writeLinesInput, expect string) {
+		filePath := NewPath(t.TempDir()).JoinStrings("file")
+		assert.NoError(t, os.WriteFile(filePath.String(), []byte("previous content"), 0644))
+
+		assert.NoError(t, filePath.WriteLines(input.lines, input.opts))
+
+		content, err := os.ReadFile(filePath.String())
+		assert.NoError(t, err)
+		assert.Equal(t, expect, string(content))
+
+		// lines survive a round trip unless the last one is empty and unterminated
+		lines, err := filePath.ReadLines()
+		assert.NoError(t, err)
+		if len(input.lines) > 0 && !(input.opts.OmitTrailingNewline && input.lines[len(input.lines)-1] == "") {
+			assert.Equal(t, input.lines, lines)
+		}
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("permissions are not supported on windows")
+		}
+
+		filePath := NewPath(t.TempDir()).JoinStrings("file")
+		assert.NoError(t, filePath.WriteLines([]string{"secret"}, LineOptions{Perm: 0600}))
+
+		info, err := filePath.Stat()
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode.Perm())
+	})
+}
+
+func TestPath_MkdirTempCreateTemp(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	cases := []TestCase[[]string, string]{
+		{Input: []string{"", "tmp-*"}, Expect: "tmp-"},
+		{Input: []string{"", "*.tmp"}, Expect: ".tmp"},
+		{Input: []string{"", ""}, Expect: ""},
+		{Input: []string{"missing", "tmp-*"}, Error: true},
+		{Input: []string{"", "invalid/*"}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []string, expect string, error bool) {
+		assert.Len(t, input, 2)
+		dir := tempPath.JoinStrings(input[0])
+
+		t.Run("MkdirTemp", func(t *testing.T) {
+			tempDir, err := dir.MkdirTemp(input[1])
+			assert.Equal(t, error, err != nil)
+
+			if !error {
+				assert.True(t, tempDir.IsDir())
+				assert.True(t, tempDir.Parent().Equals(dir))
+				assert.Contains(t, tempDir.Base(), expect)
+			}
+		})
+
+		t.Run("CreateTemp", func(t *testing.T) {
+			tempFile, file, err := dir.CreateTemp(input[1])
+			assert.Equal(t, error, err != nil)
+
+			if !error {
+				assert.NoError(t, file.Close())
+				assert.True(t, tempFile.IsFile())
+				assert.True(t, tempFile.Parent().Equals(dir))
+				assert.Contains(t, tempFile.Base(), expect)
+			}
+		})
+	})
+}
+
+func TestPath_TempChild(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	cases := []TestCase[string, [2]string]{
+		{Input: "tmp-*", Expect: [2]string{"tmp-", ""}},
+		{Input: "*.tmp", Expect: [2]string{"", ".tmp"}},
+		{Input: "a*b*c", Expect: [2]string{"a*b", "c"}},
+		{Input: "tmp-", Expect: [2]string{"tmp-", ""}},
+		{Input: "invalid/*", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect [2]string, error bool) {
+		child, err := tempPath.TempChild(input)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.True(t, child.Parent().Equals(tempPath))
+			assert.False(t, child.Exists())
+			assert.True(t, strings.HasPrefix(child.Base(), expect[0]))
+			assert.True(t, strings.HasSuffix(child.Base(), expect[1]))
+			assert.Greater(t, len(child.Base()), len(expect[0])+len(expect[1]))
+
+			other, err := tempPath.TempChild(input)
+			assert.NoError(t, err)
+			assert.False(t, other.Equals(child))
+		}
+	})
+}
+
+func TestPath_Checksum(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("empty").String(), nil, 0644))
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("hello").String(), []byte("hello world"), 0644))
+
+	// the expected slice holds the SHA-256 and MD5 checksum
+	cases := []TestCase[string, []string]{
+		{Input: "empty", Expect: []string{
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			"d41d8cd98f00b204e9800998ecf8427e",
+		}},
+		{Input: "hello", Expect: []string{
+			"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			"5eb63bbbe01eeed093cb22bb8f5acdc3",
+		}},
+		{Input: "missing", Error: true},
+		{Input: "", Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input string, expect []string, error bool) {
+		path := tempPath.JoinStrings(input)
+
+		sha256Sum, sha256Err := path.SHA256()
+		md5Sum, md5Err := path.MD5()
+		checksum, checksumErr := path.Checksum(sha256.New)
+
+		assert.Equal(t, error, sha256Err != nil)
+		assert.Equal(t, error, md5Err != nil)
+		assert.Equal(t, error, checksumErr != nil)
+
+		if !error {
+			assert.Len(t, expect, 2)
+			assert.Equal(t, expect[0], sha256Sum)
+			assert.Equal(t, expect[1], md5Sum)
+			assert.Equal(t, sha256Sum, checksum)
+		}
+	})
+}
+
+func TestPath_TreeChecksum(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	// createTree creates a directory tree with the passed files and contents
+	createTree := func(t *testing.T, name string, files map[string]string) *Path {
+		root := tempPath.JoinStrings(name)
+		assert.NoError(t, root.Mkdir(0755))
+
+		for file, content := range files {
+			filePath := root.JoinStrings(file)
+			assert.NoError(t, filePath.Parent().MkdirAll(0755))
+			assert.NoError(t, os.WriteFile(filePath.String(), []byte(content), 0644))
+		}
+
+		return root
+	}
+
+	files := map[string]string{"a": "a", "dir/b": "b", "dir/nested/c": "c"}
+
+	original := createTree(t, "original", files)
+	identical := createTree(t, "identical", files)
+	changedContent := createTree(t, "content", map[string]string{"a": "a", "dir/b": "B", "dir/nested/c": "c"})
+	renamed := createTree(t, "renamed", map[string]string{"a": "a", "dir/b2": "b", "dir/nested/c": "c"})
+	extra := createTree(t, "extra", map[string]string{"a": "a", "dir/b": "b", "dir/nested/c": "c", "vendor/d": "d"})
+	emptyDir := createTree(t, "empty-dir", files)
+	assert.NoError(t, emptyDir.JoinStrings("empty").Mkdir(0755))
+
+	// identical contents in other files must not produce the same checksum
+	moved := createTree(t, "moved", map[string]string{"a": "", "dir/b": "ab", "dir/nested/c": "c"})
+
+	checksum := func(t *testing.T, p *Path, opts TreeChecksumOptions) string {
+		sum, err := p.TreeChecksum(opts)
+		assert.NoError(t, err)
+		return sum
+	}
+
+	originalSum := checksum(t, original, TreeChecksumOptions{})
+	assert.Len(t, originalSum, 64)
+
+	assert.Equal(t, originalSum, checksum(t, identical, TreeChecksumOptions{}))
+	assert.Equal(t, originalSum, checksum(t, extra, TreeChecksumOptions{Exclude: []string{"vendor"}}))
+
+	for _, other := range []*Path{changedContent, renamed, extra, emptyDir, moved} {
+		assert.NotEqual(t, originalSum, checksum(t, other, TreeChecksumOptions{}), other.Base())
+	}
+
+	t.Run("options", func(t *testing.T) {
+		assert.Len(t, checksum(t, original, TreeChecksumOptions{Hash: md5.New}), 32)
+
+		if runtime.GOOS != "windows" {
+			assert.NoError(t, identical.JoinStrings("a").Chmod(0600))
+			assert.Equal(t, originalSum, checksum(t, identical, TreeChecksumOptions{}))
+			assert.NotEqual(t,
+				checksum(t, original, TreeChecksumOptions{IncludeModes: true}),
+				checksum(t, identical, TreeChecksumOptions{IncludeModes: true}),
+			)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := tempPath.JoinStrings("missing").TreeChecksum(TreeChecksumOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestPath_Copy(t *testing.T) {
+	cases := []TestCase[*Path, interface{}]{
+		{Input: NewPath("foo/bar")},
+		{Input: NewPath("../foo/bar")},
+		{Input: NewPath("..")},
+		{Input: NewPath("/foo")},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect interface{}) {
+		pointerCopy := input
+		copiedPath := input.Copy()
+
+		// compare pointers
+		assert.True(t, input == pointerCopy)
+		assert.False(t, input == copiedPath)
+
+		// ensure copied path has same contents as original
+		assert.Equal(t, input, copiedPath)
+	})
+}
+
+func TestCompare(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+
+	files := map[string]string{
+		"a":     "content",
+		"b":     "content",
+		"c":     "CONTENT",
+		"d":     "longer content",
+		"empty": "",
+	}
+	for name, content := range files {
+		err := os.WriteFile(tempPath.JoinStrings(name).String(), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	err := os.Symlink("a", tempPath.JoinStrings("link-a").String())
+	assert.NoError(t, err)
+	err = os.Symlink("b", tempPath.JoinStrings("link-b").String())
+	assert.NoError(t, err)
+
+	// align modification times to only compare the intended properties
+	for name := range files {
+		err = os.Chtimes(tempPath.JoinStrings(name).String(), time.Time{}, time.Unix(0, 0))
+		assert.NoError(t, err)
+	}
+
+	cases := []TestCase[[]string, Comparison]{
+		{Input: []string{"a", "b"}, Expect: Comparison{}},
+		{Input: []string{"a", "c"}, Expect: Comparison{Content: true}},
+		{Input: []string{"a", "d"}, Expect: Comparison{Size: true, Content: true}},
+		{Input: []string{"a", "empty"}, Expect: Comparison{Size: true, Content: true}},
+		{Input: []string{"link-a", "link-b"}, Expect: Comparison{LinkTarget: true}},
+		{Input: []string{"a", "non-existing"}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%s]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input []string, expect Comparison, error bool) {
+		assert.Len(t, input, 2)
+
+		comparison, err := Compare(tempPath.JoinStrings(input[0]), tempPath.JoinStrings(input[1]), true)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			// symlink modification times are not aligned
+			comparison.ModTime = false
+
+			assert.Equal(t, expect, *comparison)
+			assert.Equal(t, expect != Comparison{}, comparison.Differs())
+		}
+	})
+
+	t.Run("without content", func(t *testing.T) {
+		comparison, err := Compare(tempPath.JoinStrings("a"), tempPath.JoinStrings("c"), false)
+		assert.NoError(t, err)
+		assert.False(t, comparison.Differs())
+	})
+}
+
+func TestPath_CompareTrees(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	left := tempPath.JoinStrings("left")
+	right := tempPath.JoinStrings("right")
+
+	leftFiles := map[string]string{"same": "same", "size": "short", "content": "aaaa", "time": "time", "left-only/file": "", "type": "", "vendor/x": ""}
+	rightFiles := map[string]string{"same": "same", "size": "longer", "content": "bbbb", "time": "time", "right-only": "", "type/file": ""}
+
+	for root, files := range map[*Path]map[string]string{left: leftFiles, right: rightFiles} {
+		for name, content := range files {
+			filePath := root.JoinStrings(name)
+			assert.NoError(t, filePath.Parent().MkdirAll(0755))
+			assert.NoError(t, os.WriteFile(filePath.String(), []byte(content), 0644))
+			assert.NoError(t, filePath.Chtimes(time.Unix(0, 0), time.Unix(0, 0)))
+		}
+	}
+	assert.NoError(t, right.JoinStrings("time").Chtimes(time.Unix(100, 0), time.Unix(100, 0)))
+
+	// paths converts relative path strings into Paths
+	paths := func(names ...string) []*Path {
+		var result []*Path
+		for _, name := range names {
+			result = append(result, NewPath(name))
+		}
+		return result
+	}
+
+	cases := []TestCase[CompareTreesOptions, TreeDiff]{
+		{Input: CompareTreesOptions{}, Expect: TreeDiff{
+			OnlyLeft:  paths("left-only", "left-only/file", "vendor", "vendor/x"),
+			OnlyRight: paths("right-only", "type/file"),
+			Differing: paths("size", "type"),
+		}},
+		{Input: CompareTreesOptions{ModTime: true, Content: true, Exclude: []string{"vendor"}}, Expect: TreeDiff{
+			OnlyLeft:  paths("left-only", "left-only/file"),
+			OnlyRight: paths("right-only", "type/file"),
+			Differing: paths("content", "size", "time", "type"),
+		}},
+		{Input: CompareTreesOptions{Exclude: []string{"[z"}}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%v]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input CompareTreesOptions, expect TreeDiff, error bool) {
+		diff, err := left.CompareTrees(right, input)
+		assert.Equal(t, error, err != nil)
+
+		if !error {
+			assert.Equal(t, expect, diff)
+			assert.False(t, diff.Equal())
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		diff, err := left.CompareTrees(left, CompareTreesOptions{ModTime: true, Content: true})
+		assert.NoError(t, err)
+		assert.True(t, diff.Equal())
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := left.CompareTrees(left.JoinStrings("same"), CompareTreesOptions{})
+		assert.Error(t, err)
+
+		_, err = left.CompareTrees(tempPath.JoinStrings("missing"), CompareTreesOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestPath_SyncTo(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	source := tempPath.JoinStrings("source")
+
+	sourceFiles := map[string]string{"same": "same", "changed": "newer", "new/file": "new", "type/file": "", "vendor/x": ""}
+	destFiles := map[string]string{"same": "same", "changed": "old", "extra/file": "", "type": "", "vendor/y": ""}
+
+	// populate creates the passed files below root with equal modification times
+	populate := func(root *Path, files map[string]string) {
+		for name, content := range files {
+			filePath := root.JoinStrings(name)
+			assert.NoError(t, filePath.Parent().MkdirAll(0755))
+			assert.NoError(t, os.WriteFile(filePath.String(), []byte(content), 0644))
+			assert.NoError(t, filePath.Chtimes(time.Unix(0, 0), time.Unix(0, 0)))
+		}
+	}
+	populate(source, sourceFiles)
+	assert.NoError(t, source.JoinStrings("changed").Chmod(0600))
+
+	// paths converts relative path strings into Paths
+	paths := func(names ...string) []*Path {
+		var result []*Path
+		for _, name := range names {
+			result = append(result, NewPath(name))
+		}
+		return result
+	}
+
+	cases := []TestCase[SyncOptions, SyncReport]{
+		{Input: SyncOptions{}, Expect: SyncReport{
+			Copied:  paths("new", "new/file", "type/file", "vendor/x"),
+			Updated: paths("changed", "type"),
+		}},
+		{Input: SyncOptions{DryRun: true, Delete: true}, Expect: SyncReport{
+			Copied:  paths("new", "new/file", "type/file", "vendor/x"),
+			Updated: paths("changed", "type"),
+			Deleted: paths("extra", "extra/file", "vendor/y"),
+		}},
+		{Input: SyncOptions{Delete: true, Checksum: true, Exclude: []string{"vendor"}}, Expect: SyncReport{
+			Copied:  paths("new", "new/file", "type/file"),
+			Updated: paths("changed", "type"),
+			Deleted: paths("extra", "extra/file"),
+		}},
+		{Input: SyncOptions{Exclude: []string{"[z"}}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%v]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input SyncOptions, expect SyncReport, error bool) {
+		dest := NewPath(t.TempDir())
+		populate(dest, destFiles)
+
+		report, err := source.SyncTo(dest, input)
+		assert.Equal(t, error, err != nil)
+		if error {
+			return
+		}
+
+		assert.Equal(t, expect, report)
+
+		diff, err := source.CompareTrees(dest, CompareTreesOptions{ModTime: true, Content: true, Exclude: input.Exclude})
+		assert.NoError(t, err)
+		if input.DryRun {
+			assert.Equal(t, report.Copied, diff.OnlyLeft)
+			assert.Equal(t, report.Updated, diff.Differing)
+		} else {
+			assert.Empty(t, diff.OnlyLeft)
+			assert.Empty(t, diff.Differing)
+			assert.Equal(t, input.Delete, len(diff.OnlyRight) == 0)
+
+			info, err := dest.JoinStrings("changed").Stat()
+			assert.NoError(t, err)
+			if runtime.GOOS != "windows" {
+				assert.Equal(t, os.FileMode(0600), info.Mode.Perm())
+			}
+
+			// a second run has nothing left to do
+			report, err = source.SyncTo(dest, input)
+			assert.NoError(t, err)
+			assert.Equal(t, SyncReport{}, report)
+		}
+	})
+
+	t.Run("missing destination", func(t *testing.T) {
+		dest := tempPath.JoinStrings("missing")
+
+		report, err := source.SyncTo(dest, SyncOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.Len(t, report.Copied, 8)
+		assert.False(t, dest.Exists())
+
+		_, err = source.SyncTo(dest, SyncOptions{})
+		assert.NoError(t, err)
+
+		diff, err := source.CompareTrees(dest, CompareTreesOptions{ModTime: true, Content: true})
+		assert.NoError(t, err)
+		assert.True(t, diff.Equal())
+	})
+
+	t.Run("directory modes", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("directory permissions are not supported on Windows")
+		}
+
+		modeSource := tempPath.JoinStrings("modeSource")
+		dest := tempPath.JoinStrings("modeDest")
+		populate(modeSource, map[string]string{"dir/file": "file"})
+		populate(dest, map[string]string{"dir/file": "file"})
+		assert.NoError(t, modeSource.JoinStrings("dir").Chmod(0700))
+		assert.NoError(t, dest.JoinStrings("dir").Chmod(0755))
+
+		report, err := modeSource.SyncTo(dest, SyncOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, SyncReport{Updated: paths("dir")}, report)
+
+		info, err := dest.JoinStrings("dir").Stat()
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), info.Mode.Perm())
+		assert.True(t, dest.JoinStrings("dir", "file").Exists())
+
+		report, err = modeSource.SyncTo(dest, SyncOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, SyncReport{}, report)
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := source.JoinStrings("same").SyncTo(tempPath.JoinStrings("dest"), SyncOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestPath_ZipTo(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	source := tempPath.JoinStrings("source")
+
+	for _, name := range []string{"b.txt", "a/c.go", "a/d.txt", "vendor/e.go", "empty/"} {
+		filePath := source.JoinStrings(name)
+		if strings.HasSuffix(name, "/") {
+			assert.NoError(t, filePath.MkdirAll(0755))
+			continue
+		}
+
+		assert.NoError(t, filePath.Parent().MkdirAll(0755))
+		assert.NoError(t, os.WriteFile(filePath.String(), []byte(name), 0644))
+	}
+
+	hasSymlinks := runtime.GOOS != "windows"
+	if hasSymlinks {
+		assert.NoError(t, os.Symlink("b.txt", source.JoinStrings("link").String()))
+	}
+
+	cases := []TestCase[ArchiveOptions, []string]{
+		{Input: ArchiveOptions{}, Expect: []string{"a/", "a/c.go", "a/d.txt", "b.txt", "empty/", "link", "vendor/", "vendor/e.go"}},
+		{Input: ArchiveOptions{Include: []string{"**/*.go"}, Exclude: []string{"vendor"}}, Expect: []string{"a/", "a/c.go"}},
+		{Input: ArchiveOptions{Include: []string{"a", "empty"}, Exclude: []string{"**/*.txt"}}, Expect: []string{"a/", "a/c.go", "empty/"}},
+		{Input: ArchiveOptions{Include: []string{"[z"}}, Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%v]", testCase.Input)
+		if !hasSymlinks {
+			cases[i].Expect = slices.DeleteFunc(testCase.Expect, func(name string) bool { return name == "link" })
+		}
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input ArchiveOptions, expect []string, error bool) {
+		dest := NewPath(t.TempDir()).JoinStrings("archive.zip")
+
+		err := source.ZipTo(dest, input)
+		assert.Equal(t, error, err != nil)
+		if error {
+			assert.False(t, dest.Exists())
+			return
+		}
+
+		reader, err := zip.OpenReader(dest.String())
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		var names []string
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+
+			if file.Name == "a/c.go" {
+				assert.Equal(t, os.FileMode(0644), file.Mode().Perm())
+
+				content, err := file.Open()
+				assert.NoError(t, err)
+				data, err := io.ReadAll(content)
+				assert.NoError(t, err)
+				assert.Equal(t, "a/c.go", string(data))
+				_ = content.Close()
+			}
+
+			if file.Name == "link" {
+				assert.Equal(t, fs.ModeSymlink, file.Mode().Type())
+			}
+		}
+
+		assert.Equal(t, expect, names)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		dest := tempPath.JoinStrings("file.zip")
+		assert.NoError(t, source.JoinStrings("b.txt").ZipTo(dest, ArchiveOptions{}))
+
+		reader, err := zip.OpenReader(dest.String())
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		assert.Len(t, reader.File, 1)
+		assert.Equal(t, "b.txt", reader.File[0].Name)
+	})
+
+	t.Run("archive inside source", func(t *testing.T) {
+		dest := source.JoinStrings("self.zip")
+		defer func() { _ = dest.Unlink(true) }()
+
+		assert.NoError(t, source.ZipTo(dest, ArchiveOptions{}))
+		assert.NoError(t, source.ZipTo(dest, ArchiveOptions{}))
+
+		reader, err := zip.OpenReader(dest.String())
+		assert.NoError(t, err)
+		defer reader.Close()
+
+		for _, file := range reader.File {
+			assert.NotEqual(t, "self.zip", file.Name)
+		}
+	})
+}
+
+func TestPath_TarTo(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	source := tempPath.JoinStrings("source")
+
+	assert.NoError(t, source.JoinStrings("dir").MkdirAll(0755))
+	assert.NoError(t, os.WriteFile(source.JoinStrings("dir", "file").String(), []byte("content"), 0640))
+	assert.NoError(t, os.WriteFile(source.JoinStrings("script").String(), []byte("#!/bin/sh"), 0755))
+
+	hasSymlinks := runtime.GOOS != "windows"
+	if hasSymlinks {
+		assert.NoError(t, os.Symlink("script", source.JoinStrings("link").String()))
+	}
+
+	cases := []TestCase[Compression, bool]{
+		{Input: CompressionNone, Expect: true},
+		{Input: CompressionGzip, Expect: true},
+		{Input: Compression(42), Error: true},
+	}
+
+	for i, testCase := range cases {
+		cases[i].Name = fmt.Sprintf("[%d]", testCase.Input)
+	}
+
+	runForResultsE(t, cases, func(t *testing.T, input Compression, expect bool, error bool) {
+		dest := NewPath(t.TempDir()).JoinStrings("archive.tar")
+
+		err := source.TarTo(dest, input)
+		assert.Equal(t, error, err != nil)
+		if error {
+			assert.False(t, dest.Exists())
+			return
+		}
+
+		file, err := dest.OpenRead()
+		assert.NoError(t, err)
+		defer file.Close()
+
+		var reader io.Reader = file
+		if input == CompressionGzip {
+			gzipReader, err := gzip.NewReader(file)
+			assert.NoError(t, err)
+			reader = gzipReader
+		}
+
+		headers := make(map[string]*tar.Header)
+		var names []string
+
+		tarReader := tar.NewReader(reader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+
+			names = append(names, header.Name)
+			headers[header.Name] = header
+
+			if header.Name == "dir/file" {
+				data, err := io.ReadAll(tarReader)
+				assert.NoError(t, err)
+				assert.Equal(t, "content", string(data))
+			}
+		}
+
+		expectedNames := []string{"dir/", "dir/file", "link", "script"}
+		if !hasSymlinks {
+			expectedNames = []string{"dir/", "dir/file", "script"}
+		}
+		assert.Equal(t, expectedNames, names)
+
+		if runtime.GOOS != "windows" {
+			assert.Equal(t, int64(0640), headers["dir/file"].Mode&0777)
+			assert.Equal(t, int64(0755), headers["script"].Mode&0777)
+		}
+
+		if hasSymlinks {
+			assert.Equal(t, byte(tar.TypeSymlink), headers["link"].Typeflag)
+			assert.Equal(t, "script", headers["link"].Linkname)
+		}
+	})
+}
+
+func TestPath_ExtractTo(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	source := tempPath.JoinStrings("source")
+
+	assert.NoError(t, source.JoinStrings("dir").MkdirAll(0755))
+	assert.NoError(t, os.WriteFile(source.JoinStrings("dir", "file").String(), []byte("content"), 0600))
+	assert.NoError(t, source.JoinStrings("dir", "file").Chtimes(time.Unix(1000, 0), time.Unix(1000, 0)))
+	if runtime.GOOS != "windows" {
+		assert.NoError(t, os.Symlink("dir/file", source.JoinStrings("link").String()))
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		archives := map[string]func(dest *Path) error{
+			"zip":    func(dest *Path) error { return source.ZipTo(dest, ArchiveOptions{}) },
+			"tar":    func(dest *Path) error { return source.TarTo(dest, CompressionNone) },
+			"tar.gz": func(dest *Path) error { return source.TarTo(dest, CompressionGzip) },
+		}
+
+		for name, create := range archives {
+			archive := tempPath.JoinStrings("archive." + name)
+			assert.NoError(t, create(archive), name)
+
+			dest := tempPath.JoinStrings("extracted-" + name)
+			assert.NoError(t, archive.ExtractTo(dest), name)
+
+			diff, err := source.CompareTrees(dest, CompareTreesOptions{ModTime: true, Mode: true, Content: true})
+			assert.NoError(t, err, name)
+			assert.True(t, diff.Equal(), name)
+
+			// existing files are not overwritten
+			assert.Error(t, archive.ExtractTo(dest), name)
+		}
+	})
+
+	// zipArchive writes a zip archive with the passed entries and returns its Path
+	zipArchive := func(entries map[string]string, symlinks map[string]string) *Path {
+		archive := NewPath(t.TempDir()).JoinStrings("archive.zip")
+		file, err := os.Create(archive.String())
+		assert.NoError(t, err)
+		defer file.Close()
+
+		zipWriter := zip.NewWriter(file)
+		for name, content := range entries {
+			w, err := zipWriter.Create(name)
+			assert.NoError(t, err)
+			_, err = io.WriteString(w, content)
+			assert.NoError(t, err)
+		}
+		for name, target := range symlinks {
+			header := &zip.FileHeader{Name: name}
+			header.SetMode(fs.ModeSymlink | 0777)
+			w, err := zipWriter.CreateHeader(header)
+			assert.NoError(t, err)
+			_, err = io.WriteString(w, target)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, zipWriter.Close())
+
+		return archive
+	}
+
+	// tarArchive writes a tar archive with the passed headers and returns its Path
+	tarArchive := func(headers ...*tar.Header) *Path {
+		archive := NewPath(t.TempDir()).JoinStrings("archive.tar")
+		file, err := os.Create(archive.String())
+		assert.NoError(t, err)
+		defer file.Close()
+
+		tarWriter := tar.NewWriter(file)
+		for _, header := range headers {
+			assert.NoError(t, tarWriter.WriteHeader(header))
+		}
+		assert.NoError(t, tarWriter.Close())
+
+		return archive
+	}
+
+	unsupported := tempPath.JoinStrings("unsupported")
+	assert.NoError(t, os.WriteFile(unsupported.String(), []byte("no archive"), 0644))
+
+	cases := []TestCase[*Path, bool]{
+		{Name: "parent traversal", Input: zipArchive(map[string]string{"../evil": ""}, nil)},
+		{Name: "nested traversal", Input: zipArchive(map[string]string{"dir/../../evil": ""}, nil)},
+		{Name: "absolute path", Input: tarArchive(&tar.Header{Name: "/evil", Mode: 0644, Typeflag: tar.TypeReg})},
+		{Name: "escaping symlink", Input: zipArchive(nil, map[string]string{"link": "../outside"})},
+		{Name: "absolute symlink", Input: tarArchive(&tar.Header{Name: "link", Linkname: "/etc", Typeflag: tar.TypeSymlink})},
+		{Name: "entry below symlink", Input: tarArchive(
+			&tar.Header{Name: "link", Linkname: ".", Typeflag: tar.TypeSymlink},
+			&tar.Header{Name: "link/evil", Linkname: "..", Typeflag: tar.TypeSymlink},
+		)},
+		{Name: "chained escaping symlinks", Input: tarArchive(
+			&tar.Header{Name: "a/", Mode: 0755, Typeflag: tar.TypeDir},
+			&tar.Header{Name: "a/sub", Linkname: "..", Typeflag: tar.TypeSymlink},
+			&tar.Header{Name: "link", Linkname: "a/sub/../outside", Typeflag: tar.TypeSymlink},
+		)},
+		{Name: "chained escaping symlinks in reverse order", Input: tarArchive(
+			&tar.Header{Name: "a/", Mode: 0755, Typeflag: tar.TypeDir},
+			&tar.Header{Name: "link", Linkname: "a/sub/../outside", Typeflag: tar.TypeSymlink},
+			&tar.Header{Name: "a/sub", Linkname: "..", Typeflag: tar.TypeSymlink},
+		)},
+		{Name: "chained local symlinks", Input: tarArchive(
+			&tar.Header{Name: "a/", Mode: 0755, Typeflag: tar.TypeDir},
+			&tar.Header{Name: "a/sub", Linkname: "..", Typeflag: tar.TypeSymlink},
+			&tar.Header{Name: "link", Linkname: "a/sub/a", Typeflag: tar.TypeSymlink},
+		), Expect: true},
+		{Name: "directory over file", Input: tarArchive(
+			&tar.Header{Name: "file", Mode: 0644, Typeflag: tar.TypeReg},
+			&tar.Header{Name: "file/", Mode: 0700, Typeflag: tar.TypeDir},
+		)},
+		{Name: "hard link", Input: tarArchive(&tar.Header{Name: "link", Linkname: "file", Typeflag: tar.TypeLink})},
+		{Name: "unknown format", Input: unsupported},
+		{Name: "local entries", Input: zipArchive(map[string]string{"./dir/../file": "", "other/file": ""}, nil), Expect: true},
+	}
+
+	runForResults(t, cases, func(t *testing.T, input *Path, expect bool) {
+		root := NewPath(t.TempDir())
+		dest := root.JoinStrings("dest")
+
+		err := input.ExtractTo(dest)
+		assert.Equal(t, expect, err == nil)
+
+		// escaping symbolic links must not remain
+		_ = filepath.WalkDir(dest.String(), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type()&fs.ModeSymlink != 0 {
+				resolved, err := filepath.EvalSymlinks(path)
+				if err == nil {
+					assert.True(t, NewPath(resolved).IsWithin(dest), path)
+				}
+			}
+			return nil
+		})
+
+		// nothing must be created outside of dest
+		entries, err := os.ReadDir(root.String())
+		assert.NoError(t, err)
+		if input == unsupported {
+			assert.Empty(t, entries)
+		} else {
+			assert.Len(t, entries, 1)
+		}
+	})
+}
+
+func TestPath_Watch(t *testing.T) {
+	tempPath := NewPath(t.TempDir())
+	filePath := tempPath.JoinStrings("file")
+
+	events, stop := tempPath.Watch(10 * time.Millisecond)
+	defer stop()
+
+	// next receives the next event or fails after a timeout
+	next := func() ChangeEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return ChangeEvent{}
+		}
+	}
+
+	assert.NoError(t, os.WriteFile(filePath.String(), []byte("content"), 0644))
+	assert.Equal(t, ChangeEvent{Path: filePath, Kind: ChangeCreate}, next())
+
+	assert.NoError(t, os.WriteFile(filePath.String(), []byte("changed content"), 0644))
+	assert.Equal(t, ChangeEvent{Path: filePath, Kind: ChangeModify}, next())
+
+	// changes below children are not watched
+	assert.NoError(t, tempPath.JoinStrings("dir").Mkdir(0755))
+	assert.Equal(t, ChangeEvent{Path: tempPath.JoinStrings("dir"), Kind: ChangeCreate}, next())
+	assert.NoError(t, os.WriteFile(tempPath.JoinStrings("dir", "nested").String(), []byte{}, 0644))
+
+	assert.NoError(t, os.Remove(filePath.String()))
+	assert.Equal(t, ChangeEvent{Path: filePath, Kind: ChangeDelete}, next())
+
+	stop()
+	stop()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	assert.Equal(t, "modify", ChangeModify.String())
+}
+
+func TestPath_WatchTree(t *testing.T) {
+	for _, forcePolling := range []bool{false, true} {
+		t.Run(fmt.Sprintf("[ForcePolling=%t]", forcePolling), func(t *testing.T) {
+			tempPath := NewPath(t.TempDir())
+			assert.NoError(t, tempPath.JoinStrings("existing").Mkdir(0755))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events, err := tempPath.WatchTree(ctx, WatchOptions{
+				Interval:     10 * time.Millisecond,
+				Exclude:      []string{"**/a-excluded"},
+				ForcePolling: forcePolling,
+			})
+			assert.NoError(t, err)
+
+			// waitFor receives events until the expected one and returns the skipped ones
+			waitFor := func(expect FsEvent) []FsEvent {
+				var skipped []FsEvent
+				for {
+					select {
+					case event := <-events:
+						if event.Err != nil {
+							t.Fatalf("unexpected error while waiting for %s of %s: %v", expect.Kind, expect.Path, event.Err)
+						}
+						if event.Path.path == expect.Path.path && event.Kind == expect.Kind {
+							assert.Equal(t, expect.IsDir, event.IsDir)
+							return skipped
+						}
+						skipped = append(skipped, event)
+					case <-time.After(5 * time.Second):
+						t.Fatalf("timed out waiting for %s of %s", expect.Kind, expect.Path)
+						return nil
+					}
+				}
+			}
+
+			nestedDir := tempPath.JoinStrings("existing", "nested")
+			nestedFile := nestedDir.JoinStrings("file")
+
+			assert.NoError(t, nestedDir.Mkdir(0755))
+			waitFor(FsEvent{ChangeEvent: ChangeEvent{Path: nestedDir, Kind: ChangeCreate}, IsDir: true})
+
+			assert.NoError(t, os.WriteFile(nestedFile.String(), []byte("content"), 0644))
+			waitFor(FsEvent{ChangeEvent: ChangeEvent{Path: nestedFile, Kind: ChangeCreate}})
+
+			assert.NoError(t, os.WriteFile(nestedFile.String(), []byte("changed content"), 0644))
+			waitFor(FsEvent{ChangeEvent: ChangeEvent{Path: nestedFile, Kind: ChangeModify}})
+
+			assert.NoError(t, os.WriteFile(nestedDir.JoinStrings("a-excluded").String(), []byte{}, 0644))
+			assert.NoError(t, os.WriteFile(nestedDir.JoinStrings("z-sentinel").String(), []byte{}, 0644))
+			skipped := waitFor(FsEvent{ChangeEvent: ChangeEvent{Path: nestedDir.JoinStrings("z-sentinel"), Kind: ChangeCreate}})
+			for _, event := range skipped {
+				assert.NotEqual(t, "a-excluded", event.Path.Base())
+			}
+
+			assert.NoError(t, os.Remove(nestedFile.String()))
+			waitFor(FsEvent{ChangeEvent: ChangeEvent{Path: nestedFile, Kind: ChangeDelete}})
+
+			cancel()
+			for range events {
+				// drain the remaining events until the channel is closed
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		filePath := tempPath.JoinStrings("file")
+		assert.NoError(t, os.WriteFile(filePath.String(), []byte{}, 0644))
+
+		_, err := filePath.WatchTree(context.Background(), WatchOptions{})
+		assert.Error(t, err)
+
+		_, err = tempPath.WatchTree(context.Background(), WatchOptions{Exclude: []string{"[z"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestSwapDirs(t *testing.T) {
+	// createDir creates a directory containing a single marker file
+	createDir := func(t *testing.T, dir *Path, marker string) {
+		err := os.Mkdir(dir.String(), 0755)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(dir.JoinStrings(marker).String(), nil, 0644)
+		assert.NoError(t, err)
+	}
+
+	t.Run("rename", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		current := tempPath.JoinStrings("current")
+		staged := tempPath.JoinStrings("staged")
+		createDir(t, current, "old")
+		createDir(t, staged, "new")
+
+		old, err := SwapDirs(current, staged, false)
+		assert.NoError(t, err)
+		assert.Nil(t, old)
+
+		assert.True(t, current.JoinStrings("new").IsFile())
+		assert.False(t, staged.Exists())
+
+		entries, err := os.ReadDir(tempPath.String())
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("rename and keep old", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		current := tempPath.JoinStrings("current")
+		staged := tempPath.JoinStrings("staged")
+		createDir(t, current, "old")
+		createDir(t, staged, "new")
+
+		old, err := SwapDirs(current, staged, true)
+		assert.NoError(t, err)
+		assert.NotNil(t, old)
+
+		assert.True(t, current.JoinStrings("new").IsFile())
+		assert.True(t, old.JoinStrings("old").IsFile())
+	})
+
+	t.Run("non-existing current", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		current := tempPath.JoinStrings("current")
+		staged := tempPath.JoinStrings("staged")
+		createDir(t, staged, "new")
+
+		old, err := SwapDirs(current, staged, false)
+		assert.NoError(t, err)
+		assert.Nil(t, old)
+		assert.True(t, current.JoinStrings("new").IsFile())
+	})
+
+	t.Run("non-existing staged", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		current := tempPath.JoinStrings("current")
+		createDir(t, current, "old")
+
+		_, err := SwapDirs(current, tempPath.JoinStrings("staged"), false)
+		assert.Error(t, err)
+		assert.True(t, current.JoinStrings("old").IsFile())
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		tempPath := NewPath(t.TempDir())
+		current := tempPath.JoinStrings("current")
+		release1 := tempPath.JoinStrings("release-1")
+		release2 := tempPath.JoinStrings("release-2")
+		createDir(t, release1, "old")
+		createDir(t, release2, "new")
+
+		err := os.Symlink("release-1", current.String())
+		assert.NoError(t, err)
+
+		old, err := SwapDirs(current, release2, true)
+		assert.NoError(t, err)
+		assert.Equal(t, release1, old)
+
+		target, err := os.Readlink(current.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "release-2", target)
+		assert.True(t, current.JoinStrings("new").IsFile())
+		assert.True(t, release1.Exists())
 	})
 }
 
@@ -0,0 +1,106 @@
+//go:build unix
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+/*
+fileOwner returns the numeric user and group id of the passed file info.
+The boolean return value is false if the ids are not available.
+*/
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+/*
+isCrossDeviceError returns whether the passed error was caused by
+an operation across different filesystems.
+*/
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
+
+/*
+isHidden returns whether the passed path is hidden.
+On Unix-based operating systems, files starting with a dot are hidden.
+*/
+func isHidden(path string) (bool, error) {
+	return isDotfile(path), nil
+}
+
+/*
+fileDiskSize returns the number of bytes allocated on disk for the passed file info.
+The boolean return value is false if the allocation is not available.
+*/
+func fileDiskSize(info os.FileInfo) (int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	// st_blocks is always counted in 512-byte units
+	return int64(stat.Blocks) * 512, true
+}
+
+/*
+fileID returns the device and inode number of the passed path.
+Symbolic links are followed.
+*/
+func fileID(path string) (uint64, uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, fmt.Errorf("file identifiers are not available: %w", errors.ErrUnsupported)
+	}
+
+	return uint64(stat.Dev), uint64(stat.Ino), nil
+}
+
+/*
+isJunction returns whether the passed path is an NTFS junction point.
+Junctions only exist on Windows, thus false is returned for every existing path.
+*/
+func isJunction(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	return false, err
+}
+
+/*
+fileLinkCount returns the number of hard links of the passed path.
+Symbolic links are followed.
+*/
+func fileLinkCount(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("link count is not available: %w", errors.ErrUnsupported)
+	}
+
+	return uint64(stat.Nlink), nil
+}
+
+/*
+knownFolder returns the location of a Windows known folder.
+Known folders only exist on Windows, thus false is always returned.
+*/
+func knownFolder(dir userDir) (string, bool, error) {
+	return "", false, nil
+}
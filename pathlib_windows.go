@@ -0,0 +1,310 @@
+//go:build windows
+
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+/*
+fileOwner returns the numeric user and group id of the passed file info.
+Windows does not expose numeric ownership, thus false is always returned.
+*/
+func fileOwner(info os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
+
+/*
+isCrossDeviceError returns whether the passed error was caused by
+an operation across different volumes.
+*/
+func isCrossDeviceError(err error) bool {
+	// ERROR_NOT_SAME_DEVICE
+	return errors.Is(err, syscall.Errno(17))
+}
+
+/*
+fileAccessTime returns the last access time of the passed file info.
+The boolean return value is false if the time is not available.
+*/
+func fileAccessTime(info os.FileInfo) (time.Time, bool) {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, data.LastAccessTime.Nanoseconds()), true
+}
+
+/*
+fileChangeTime returns the last status change time of the passed file info.
+Windows does not track status changes, thus false is always returned.
+*/
+func fileChangeTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+/*
+hasAccess returns whether the current process may access the passed path
+with the passed access mode, a combination of accessRead, accessWrite and accessExecute.
+
+Windows access control lists are not evaluated. Instead, every existing path is
+considered readable, paths without the read-only attribute are considered writable,
+and directories as well as files with an extension listed in PATHEXT are considered executable.
+*/
+func hasAccess(path string, mode uint32) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if mode&accessWrite != 0 && info.Mode().Perm()&0200 == 0 {
+		return false
+	}
+
+	if mode&accessExecute != 0 && !info.IsDir() && !hasExecutableExtension(path) {
+		return false
+	}
+
+	return true
+}
+
+/*
+hasExecutableExtension returns whether the passed path has an extension
+that is listed in the PATHEXT environment variable.
+*/
+func hasExecutableExtension(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	if extension == "" {
+		return false
+	}
+
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".com;.exe;.bat;.cmd"
+	}
+
+	for _, executableExtension := range strings.Split(strings.ToLower(pathExt), ";") {
+		if extension == executableExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+isHidden returns whether the passed path is hidden.
+On Windows, this is the case if the hidden file attribute is set.
+*/
+func isHidden(path string) (bool, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(systemPath(path))
+	if err != nil {
+		return false, err
+	}
+
+	attributes, err := syscall.GetFileAttributes(pathPtr)
+	if err != nil {
+		return false, &os.PathError{Op: "GetFileAttributes", Path: path, Err: err}
+	}
+
+	return attributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0, nil
+}
+
+/*
+fileDiskSize returns the number of bytes allocated on disk for the passed file info.
+The allocation is not available on this platform, thus false is always returned.
+*/
+func fileDiskSize(info os.FileInfo) (int64, bool) {
+	return 0, false
+}
+
+/*
+fileID returns the volume serial number and file index of the passed path,
+which are the Windows equivalents of device and inode numbers.
+Symbolic links are followed.
+*/
+func fileID(path string) (uint64, uint64, error) {
+	data, err := fileInformation(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint64(data.VolumeSerialNumber), uint64(data.FileIndexHigh)<<32 | uint64(data.FileIndexLow), nil
+}
+
+/*
+fileLinkCount returns the number of hard links of the passed path.
+Symbolic links are followed.
+*/
+func fileLinkCount(path string) (uint64, error) {
+	data, err := fileInformation(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(data.NumberOfLinks), nil
+}
+
+/*
+fileInformation returns the handle-based file information of the passed path.
+Symbolic links are followed.
+*/
+func fileInformation(path string) (syscall.ByHandleFileInformation, error) {
+	var data syscall.ByHandleFileInformation
+
+	pathPtr, err := syscall.UTF16PtrFromString(systemPath(path))
+	if err != nil {
+		return data, err
+	}
+
+	// FILE_FLAG_BACKUP_SEMANTICS is required to open directories
+	handle, err := syscall.CreateFile(pathPtr, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return data, &os.PathError{Op: "CreateFile", Path: path, Err: err}
+	}
+	defer syscall.CloseHandle(handle)
+
+	err = syscall.GetFileInformationByHandle(handle, &data)
+	if err != nil {
+		return data, &os.PathError{Op: "GetFileInformationByHandle", Path: path, Err: err}
+	}
+
+	return data, nil
+}
+
+// ioReparseTagMountPoint is the reparse tag of junction points (IO_REPARSE_TAG_MOUNT_POINT).
+const ioReparseTagMountPoint = 0xA0000003
+
+// fileAttributeTagInfo is the FileAttributeTagInfo class of GetFileInformationByHandleEx.
+const fileAttributeTagInfo = 9
+
+// procGetFileInformationByHandleEx is GetFileInformationByHandleEx of kernel32.dll, which is not exposed by the syscall package.
+var procGetFileInformationByHandleEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetFileInformationByHandleEx")
+
+/*
+isJunction returns whether the passed path is an NTFS junction point.
+Junctions are reparse points with the mount point reparse tag.
+
+This function utilizes GetFileInformationByHandleEx.
+*/
+func isJunction(path string) (bool, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(systemPath(path))
+	if err != nil {
+		return false, err
+	}
+
+	// FILE_FLAG_OPEN_REPARSE_POINT opens the reparse point itself instead of its target,
+	// FILE_FLAG_BACKUP_SEMANTICS is required to open directories
+	handle, err := syscall.CreateFile(pathPtr, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_OPEN_REPARSE_POINT|syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return false, &os.PathError{Op: "CreateFile", Path: path, Err: err}
+	}
+	defer syscall.CloseHandle(handle)
+
+	// FILE_ATTRIBUTE_TAG_INFO
+	var info struct {
+		FileAttributes uint32
+		ReparseTag     uint32
+	}
+
+	ret, _, err := procGetFileInformationByHandleEx.Call(
+		uintptr(handle),
+		fileAttributeTagInfo,
+		uintptr(unsafe.Pointer(&info)),
+		unsafe.Sizeof(info),
+	)
+	if ret == 0 {
+		return false, &os.PathError{Op: "GetFileInformationByHandleEx", Path: path, Err: err}
+	}
+
+	isReparsePoint := info.FileAttributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0
+	return isReparsePoint && info.ReparseTag == ioReparseTagMountPoint, nil
+}
+
+// procGetDiskFreeSpaceExW is GetDiskFreeSpaceExW of kernel32.dll, which is not exposed by the syscall package.
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+/*
+diskUsage returns the disk usage of the volume containing the passed path.
+
+This function utilizes GetDiskFreeSpaceExW.
+*/
+func diskUsage(path string) (DiskUsage, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(systemPath(path))
+	if err != nil {
+		return DiskUsage{}, err
+	}
+
+	var available, total, free uint64
+	ret, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&available)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if ret == 0 {
+		return DiskUsage{}, &os.PathError{Op: "GetDiskFreeSpaceExW", Path: path, Err: err}
+	}
+
+	return DiskUsage{
+		Total:     total,
+		Used:      total - free,
+		Available: available,
+	}, nil
+}
+
+var (
+	// procSHGetKnownFolderPath is SHGetKnownFolderPath of shell32.dll, which is not exposed by the syscall package.
+	procSHGetKnownFolderPath = syscall.NewLazyDLL("shell32.dll").NewProc("SHGetKnownFolderPath")
+
+	// procCoTaskMemFree is CoTaskMemFree of ole32.dll, which is not exposed by the syscall package.
+	procCoTaskMemFree = syscall.NewLazyDLL("ole32.dll").NewProc("CoTaskMemFree")
+)
+
+// knownFolderIDs maps well-known user directories to their KNOWNFOLDERID.
+var knownFolderIDs = map[userDir]syscall.GUID{
+	userDirDesktop:   {Data1: 0xB4BFCC3A, Data2: 0xDB2C, Data3: 0x424C, Data4: [8]byte{0xB0, 0x29, 0x7F, 0xE9, 0x9A, 0x87, 0xC6, 0x41}},
+	userDirDocuments: {Data1: 0xFDD39AD0, Data2: 0x238F, Data3: 0x46AF, Data4: [8]byte{0xAD, 0xB4, 0x6C, 0x85, 0x48, 0x03, 0x69, 0xC7}},
+	userDirDownloads: {Data1: 0x374DE290, Data2: 0x123F, Data3: 0x4565, Data4: [8]byte{0x91, 0x64, 0x39, 0xC4, 0x92, 0x5E, 0x46, 0x7B}},
+}
+
+/*
+knownFolder returns the location of a Windows known folder.
+The boolean return value is always true.
+
+This function utilizes SHGetKnownFolderPath.
+*/
+func knownFolder(dir userDir) (string, bool, error) {
+	id := knownFolderIDs[dir]
+
+	var pathPtr *uint16
+	ret, _, _ := procSHGetKnownFolderPath.Call(
+		uintptr(unsafe.Pointer(&id)),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&pathPtr)),
+	)
+	if pathPtr != nil {
+		defer procCoTaskMemFree.Call(uintptr(unsafe.Pointer(pathPtr)))
+	}
+	if ret != 0 {
+		return "", true, fmt.Errorf("SHGetKnownFolderPath failed with HRESULT %#x", ret)
+	}
+
+	length := 0
+	for *(*uint16)(unsafe.Add(unsafe.Pointer(pathPtr), length*2)) != 0 {
+		length++
+	}
+
+	return syscall.UTF16ToString(unsafe.Slice(pathPtr, length)), true, nil
+}
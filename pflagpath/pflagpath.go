@@ -0,0 +1,142 @@
+// Package pflagpath adapts go-pathlib Paths to the pflag.Value interface of
+// github.com/spf13/pflag and provides shell completion for path flags,
+// so Paths can be used in pflag and cobra CLIs without either package
+// depending on them.
+package pflagpath
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pathlib "github.com/jeftadlvw/go-pathlib"
+)
+
+const pathSeparator = string(filepath.Separator)
+
+/*
+Value wraps a Path to implement the pflag.Value interface.
+Setting a Value sets the wrapped Path.
+*/
+type Value pathlib.Path
+
+/*
+NewValue returns a Value wrapping the passed Path.
+*/
+func NewValue(p *pathlib.Path) *Value {
+	return (*Value)(p)
+}
+
+/*
+Path returns the wrapped Path.
+*/
+func (v *Value) Path() *pathlib.Path {
+	return (*pathlib.Path)(v)
+}
+
+/*
+String returns the string representation of the wrapped Path.
+*/
+func (v *Value) String() string {
+	return v.Path().String()
+}
+
+/*
+Set parses the passed flag argument into the wrapped Path. See pathlib.Path.Set for details.
+*/
+func (v *Value) Set(value string) error {
+	return v.Path().Set(value)
+}
+
+/*
+Type returns the name of the flag value type, which is "path".
+*/
+func (v *Value) Type() string {
+	return "path"
+}
+
+/*
+CompletionOptions configures the candidates returned by CompletePath.
+*/
+type CompletionOptions struct {
+	// DirsOnly excludes all entries except directories.
+	DirsOnly bool
+
+	// Extensions restricts files to the passed extensions, e.g. ".yaml",
+	// compared case-insensitively. Directories are always included.
+	Extensions []string
+
+	// Hidden includes entries starting with a dot even if the completed
+	// name does not start with a dot.
+	Hidden bool
+}
+
+/*
+CompletePath returns the shell completion candidates for the partially typed path,
+e.g. for a cobra flag completion function. The candidates are the sorted entries of
+the typed directory starting with the typed name, keeping the typed directory prefix.
+Directories end with a separator, so the completion can continue inside them.
+Errors reading the directory result in no candidates.
+
+This function utilizes os.ReadDir.
+*/
+func CompletePath(toComplete string, opts CompletionOptions) []string {
+	dirPrefix, name := "", toComplete
+	if i := strings.LastIndexAny(toComplete, `/`+pathSeparator); i >= 0 {
+		dirPrefix, name = toComplete[:i+1], toComplete[i+1:]
+	}
+
+	dir := dirPrefix
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	// entries are sorted by name
+	var candidates []string
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if !strings.HasPrefix(entryName, name) {
+			continue
+		}
+
+		if strings.HasPrefix(entryName, ".") && !strings.HasPrefix(name, ".") && !opts.Hidden {
+			continue
+		}
+
+		// symbolic links to directories are completed as directories
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(dir, entryName))
+			isDir = err == nil && info.IsDir()
+		}
+
+		if isDir {
+			candidates = append(candidates, dirPrefix+entryName+pathSeparator)
+			continue
+		}
+
+		if opts.DirsOnly {
+			continue
+		}
+
+		matchesExtension := len(opts.Extensions) == 0
+		for _, extension := range opts.Extensions {
+			if strings.EqualFold(filepath.Ext(entryName), extension) {
+				matchesExtension = true
+				break
+			}
+		}
+
+		if matchesExtension {
+			candidates = append(candidates, dirPrefix+entryName)
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,80 @@
+package pflagpath
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pathlib "github.com/jeftadlvw/go-pathlib"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestCase[I any, E any] struct {
+	Name   string
+	Input  I
+	Expect E
+	Error  bool
+}
+
+func TestValue(t *testing.T) {
+	// the subset of pflag.Value implemented without depending on pflag
+	var value interface {
+		String() string
+		Set(string) error
+		Type() string
+	} = NewValue(new(pathlib.Path))
+
+	assert.Equal(t, "path", value.Type())
+	assert.NoError(t, value.Set("/etc/app/./app.toml"))
+	assert.Equal(t, pathlib.NewPath("/etc/app/app.toml").String(), value.String())
+	assert.Error(t, value.Set(""))
+
+	// the wrapped Path is set in place
+	path := pathlib.NewPath("out")
+	assert.NoError(t, NewValue(path).Set("dist/"))
+	assert.Equal(t, pathlib.NewPath("dist"), path)
+	assert.Same(t, path, NewValue(path).Path())
+
+	var _ flag.Value = NewValue(path)
+}
+
+func TestCompletePath(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, dir := range []string{"configs", "cache", ".hidden"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(tempDir, dir), 0777))
+	}
+	for _, file := range []string{"config.yaml", "config.JSON", "cert.pem", ".env", filepath.Join("configs", "app.yaml")} {
+		assert.NoError(t, os.WriteFile(filepath.Join(tempDir, file), nil, 0666))
+	}
+	assert.NoError(t, os.Symlink(filepath.Join(tempDir, "configs"), filepath.Join(tempDir, "conflink")))
+
+	prefix := tempDir + pathSeparator
+	sep := pathSeparator
+
+	type completionInput struct {
+		toComplete string
+		opts       CompletionOptions
+	}
+
+	cases := []TestCase[completionInput, []string]{
+		{Input: completionInput{prefix + "con", CompletionOptions{}}, Expect: []string{prefix + "config.JSON", prefix + "config.yaml", prefix + "configs" + sep, prefix + "conflink" + sep}},
+		{Input: completionInput{prefix + "c", CompletionOptions{DirsOnly: true}}, Expect: []string{prefix + "cache" + sep, prefix + "configs" + sep, prefix + "conflink" + sep}},
+		{Input: completionInput{prefix + "c", CompletionOptions{Extensions: []string{".json", ".pem"}}}, Expect: []string{prefix + "cache" + sep, prefix + "cert.pem", prefix + "config.JSON", prefix + "configs" + sep, prefix + "conflink" + sep}},
+		{Input: completionInput{prefix + ".", CompletionOptions{}}, Expect: []string{prefix + ".env", prefix + ".hidden" + sep}},
+		{Input: completionInput{prefix, CompletionOptions{DirsOnly: true}}, Expect: []string{prefix + "cache" + sep, prefix + "configs" + sep, prefix + "conflink" + sep}},
+		{Input: completionInput{prefix, CompletionOptions{DirsOnly: true, Hidden: true}}, Expect: []string{prefix + ".hidden" + sep, prefix + "cache" + sep, prefix + "configs" + sep, prefix + "conflink" + sep}},
+		{Input: completionInput{prefix + "configs" + sep, CompletionOptions{}}, Expect: []string{prefix + "configs" + sep + "app.yaml"}},
+		{Input: completionInput{prefix + "missing" + sep, CompletionOptions{}}, Expect: nil},
+		{Input: completionInput{prefix + "x", CompletionOptions{}}, Expect: nil},
+	}
+
+	for _, testCase := range cases {
+		name := fmt.Sprintf("[%s %+v]", strings.TrimPrefix(testCase.Input.toComplete, tempDir), testCase.Input.opts)
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.Expect, CompletePath(testCase.Input.toComplete, testCase.Input.opts))
+		})
+	}
+}